@@ -0,0 +1,162 @@
+package tokenizer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// AddedTokenPattern defines a family of added tokens numbered from 0 to
+// `Count-1`, such as `<extra_id_0>`...`<extra_id_99>` or `<0x00>`...`<0xFF>`,
+// without registering each of them.
+//
+// Token `n` is `fmt.Sprintf(Format, n)` and has id `BaseId + n`. In a text, the
+// tokens are found with `Regexp`, whose first group captures the number.
+//
+// All the `Count` tokens are registered in the vocabulary, to convert them to
+// and from ids, so that memory grows with `Count`: patterns save matching
+// time, not memory.
+type AddedTokenPattern struct {
+	// Format of the tokens, with a single `%d`, `%x` or `%X` verb, e.g.
+	// "<extra_id_%d>" or "<0x%02X>".
+	Format string
+	// Regexp matching the tokens, whose first group captures the number in
+	// the base of the verb of `Format`. Any match whose number n is in range
+	// is token `BaseId + n`, e.g. "(?i)<0x([0-9a-f]{2})>" also matches
+	// "<0x0a>" as "<0x0A>". Decoding gives back the tokens formatted with
+	// `Format`. If empty, it is built from `Format` and only the tokens
+	// formatted exactly like `Format` match.
+	Regexp string
+	// BaseId is the id of token 0.
+	BaseId int
+	// Count is the number of tokens.
+	Count int
+	// Special specifies whether the tokens are special tokens.
+	Special bool
+	// Token holds the options (`SingleWord`, `LStrip`, `RStrip`, `Normalized`)
+	// of the tokens. Its content is ignored.
+	Token AddedToken
+}
+
+// NewAddedTokenPattern creates a family of `count` added tokens formatted with
+// the given format, from id `baseId`.
+//
+// Example:
+//
+//	// <extra_id_0>...<extra_id_99> with ids 32000...32099
+//	p := tokenizer.NewAddedTokenPattern("<extra_id_%d>", 32000, 100, true)
+func NewAddedTokenPattern(format string, baseId, count int, special bool, opts ...ATOption) AddedTokenPattern {
+	return AddedTokenPattern{
+		Format:  format,
+		BaseId:  baseId,
+		Count:   count,
+		Special: special,
+		Token:   NewAddedToken("", special, opts...),
+	}
+}
+
+var formatVerbRe = regexp.MustCompile(`%0?[0-9]*[dxX]`)
+
+// numberRe returns the regular expression of a number and its base for a
+// format verb.
+func numberRe(verb string) (string, int) {
+	switch verb[len(verb)-1] {
+	case 'x':
+		return `([0-9a-f]+)`, 16
+	case 'X':
+		return `([0-9A-F]+)`, 16
+	}
+
+	return `([0-9]+)`, 10
+}
+
+// tokenPattern is a compiled AddedTokenPattern.
+type tokenPattern struct {
+	AddedTokenPattern
+	re   *regexp.Regexp
+	base int
+}
+
+func (p AddedTokenPattern) compile() (*tokenPattern, error) {
+	verbs := formatVerbRe.FindAllStringIndex(p.Format, -1)
+	if len(verbs) != 1 {
+		return nil, fmt.Errorf("Invalid added token pattern format %q: want a single %%d, %%x or %%X verb.\n", p.Format)
+	}
+	if p.Count <= 0 || p.BaseId < 0 {
+		return nil, fmt.Errorf("Invalid added token pattern %q: count (%d) must be positive and base id (%d) not negative.\n", p.Format, p.Count, p.BaseId)
+	}
+
+	loc := verbs[0]
+	numRe, base := numberRe(p.Format[loc[0]:loc[1]])
+	expr := p.Regexp
+	if expr == "" {
+		expr = regexp.QuoteMeta(p.Format[:loc[0]]) + numRe + regexp.QuoteMeta(p.Format[loc[1]:])
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("Invalid added token pattern regexp %q: want a group capturing the number.\n", expr)
+	}
+	re.Longest()
+
+	return &tokenPattern{AddedTokenPattern: p, re: re, base: base}, nil
+}
+
+// token returns the n-th token.
+func (p AddedTokenPattern) token(n int) AddedToken {
+	tok := p.Token
+	tok.Content = fmt.Sprintf(p.Format, n)
+
+	return tok
+}
+
+// tokens returns all the tokens of the family.
+func (p AddedTokenPattern) tokens() []AddedTokenWithId {
+	tokens := make([]AddedTokenWithId, p.Count)
+	for n := range tokens {
+		tokens[n] = AddedTokenWithId{Id: p.BaseId + n, Special: p.Special, Token: p.token(n)}
+	}
+
+	return tokens
+}
+
+// next returns the leftmost-longest token of the family in the text starting
+// at or after pos, and its id. Matches out of range, or not formatted like
+// `Format` without a user `Regexp`, are not tokens. The token must still have
+// its id in vocab.
+func (p *tokenPattern) next(text string, pos int, vocab map[string]int) (setMatch, bool) {
+	for pos < len(text) {
+		loc := p.re.FindStringSubmatchIndex(text[pos:])
+		if loc == nil {
+			return setMatch{}, false
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if loc[2] >= 0 && end > start {
+			n, err := strconv.ParseInt(text[pos+loc[2]:pos+loc[3]], p.base, 0)
+			content := text[start:end]
+			if err == nil && n >= 0 && n < int64(p.Count) {
+				canonical := fmt.Sprintf(p.Format, n)
+				id, ok := vocab[canonical]
+				if ok && id == p.BaseId+int(n) && (p.Regexp != "" || canonical == content) {
+					// The canonical content identifies the token, e.g. for the
+					// special token filters of encode options.
+					tok := p.Token
+					tok.Content = canonical
+					return setMatch{token: tok, id: id, start: start, end: end}, true
+				}
+			}
+		}
+
+		// Not a token, search from the next character
+		_, size := utf8.DecodeRuneInString(text[start:])
+		if size == 0 {
+			size = 1
+		}
+		pos = start + size
+	}
+
+	return setMatch{}, false
+}
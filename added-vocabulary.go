@@ -5,9 +5,10 @@ import (
 	"log"
 	"regexp"
 	"sort"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
-	"github.com/sugarme/regexpset"
 	"github.com/sugarme/tokenizer/normalizer"
 )
 
@@ -141,18 +142,7 @@ func (at AddedToken) GetPattern(n normalizer.Normalizer) (retVal string) {
 			lastB = ``
 		}
 
-		// normalize the content
-		content := normalizer.NewNormalizedFrom(at.Content)
-		var normalized string
-		if n != nil {
-			normalizedString, err := n.Normalize(content)
-			if err != nil {
-				log.Fatal(err)
-			}
-			normalized = normalizedString.GetNormalized()
-		} else { // don't have a normalizer, just use content as is
-			normalized = at.Content
-		}
+		normalized := at.normalizedContent(n)
 
 		reStr = fmt.Sprintf("%v%v%v", firstB, regexp.QuoteMeta(normalized), lastB)
 
@@ -171,6 +161,20 @@ func (at AddedToken) GetPattern(n normalizer.Normalizer) (retVal string) {
 	return reStr
 }
 
+// normalizedContent returns the content normalized with n, as matched
+// against normalized text. n is optional.
+func (at AddedToken) normalizedContent(n normalizer.Normalizer) string {
+	if n == nil {
+		return at.Content
+	}
+	normalized, err := n.Normalize(normalizer.NewNormalizedFrom(at.Content))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return normalized.GetNormalized()
+}
+
 func isWordCharacter(r rune) bool {
 	if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || unicode.IsControl(r) || unicode.IsPunct(r) {
 		return true
@@ -178,10 +182,84 @@ func isWordCharacter(r rune) bool {
 	return false
 }
 
-// matchingSet is a set of regular expression string
+// isWordRune reports whether r is a word character (`\w` in Unicode mode).
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// matchingSet is an automaton matching the content of a set of added tokens,
+// and the patterns of families of added tokens
 type matchingSet struct {
-	regexSet regexpset.RegexpSet
+	ac       *ahoCorasick
 	ids      []int
+	tokens   []AddedToken
+	patterns []*tokenPattern
+}
+
+// setMatch is an added token found in text[start:end]. The content of the
+// token is its canonical content, which may differ from the text matched by
+// a token pattern.
+type setMatch struct {
+	token AddedToken
+	id    int
+	start int
+	end   int
+}
+
+// findAll returns the leftmost-longest, non-overlapping added tokens found in
+// the text, from left to right. Tokens of patterns must have their id in vocab.
+func (ms matchingSet) findAll(text string, vocab map[string]int) []setMatch {
+	var matches []setMatch
+
+	// Next match of the automaton (source 0) and of each pattern, searched
+	// again once the position is past its start
+	next := make([]setMatch, 1+len(ms.patterns))
+	done := make([]bool, len(next))
+	for i := range next {
+		next[i].start = -1
+	}
+
+	pos := 0
+	for pos < len(text) {
+		best := -1
+		for i := range next {
+			if !done[i] && next[i].start < pos {
+				var ok bool
+				next[i], ok = ms.next(i, text, pos, vocab)
+				done[i] = !ok
+			}
+			if done[i] {
+				continue
+			}
+			if best < 0 || next[i].start < next[best].start ||
+				(next[i].start == next[best].start && next[i].end > next[best].end) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		matches = append(matches, next[best])
+		pos = next[best].end
+	}
+
+	return matches
+}
+
+// next returns the next match of a source of the set starting at or after pos.
+func (ms matchingSet) next(source int, text string, pos int, vocab map[string]int) (setMatch, bool) {
+	if source > 0 {
+		return ms.patterns[source-1].next(text, pos, vocab)
+	}
+	if ms.ac == nil {
+		return setMatch{}, false
+	}
+	m, ok := ms.ac.next(text, pos)
+	if !ok {
+		return setMatch{}, false
+	}
+
+	return setMatch{token: ms.tokens[m.pattern], id: ms.ids[m.pattern], start: m.start, end: m.end}, true
 }
 
 // AddedVocabulary is a vocabulary built on top of the Model
@@ -215,6 +293,11 @@ type AddedVocabulary struct {
 	splitRe matchingSet
 	// A struct containing all the normalized patterns used to split on AddedTokens
 	splitNormalizedRe matchingSet
+	// Families of tokens defined by patterns, in the order the user gave them.
+	patterns []AddedTokenPattern
+	// Ids of the model tokens that have been given another id, and so no longer
+	// have their model id.
+	vacatedIds map[int]bool
 }
 
 func NewAddedVocabulary() (retVal AddedVocabulary) {
@@ -226,9 +309,41 @@ func NewAddedVocabulary() (retVal AddedVocabulary) {
 		specialTokensSet:  make(map[string]bool, 0),
 		splitRe:           matchingSet{},
 		splitNormalizedRe: matchingSet{},
+		vacatedIds:        make(map[int]bool, 0),
 	}
 }
 
+// Clone returns a deep copy of the vocabulary, that can be modified without
+// affecting av.
+func (av *AddedVocabulary) Clone() AddedVocabulary {
+	clone := AddedVocabulary{
+		addedTokenMap:    make(map[string]int, len(av.addedTokenMap)),
+		addedTokenMapR:   make(map[int]string, len(av.addedTokenMapR)),
+		addedTokens:      append([]AddedToken{}, av.addedTokens...),
+		specialTokens:    append([]AddedToken{}, av.specialTokens...),
+		specialTokensSet: make(map[string]bool, len(av.specialTokensSet)),
+		patterns:         append([]AddedTokenPattern{}, av.patterns...),
+		vacatedIds:       make(map[int]bool, len(av.vacatedIds)),
+		// Matching sets are replaced, never modified
+		splitRe:           av.splitRe,
+		splitNormalizedRe: av.splitNormalizedRe,
+	}
+	for k, v := range av.addedTokenMap {
+		clone.addedTokenMap[k] = v
+	}
+	for k, v := range av.addedTokenMapR {
+		clone.addedTokenMapR[k] = v
+	}
+	for k, v := range av.specialTokensSet {
+		clone.specialTokensSet[k] = v
+	}
+	for k, v := range av.vacatedIds {
+		clone.vacatedIds[k] = v
+	}
+
+	return clone
+}
+
 // Len returns size of the additional vocabulary
 func (av *AddedVocabulary) Len() int {
 	return len(av.addedTokenMap)
@@ -240,24 +355,40 @@ func (av *AddedVocabulary) GetVocab() (retVal map[string]int) {
 }
 
 // Get the id matching one of our token if it exists
+//
+// NOTE. Model tokens whose id has been assigned to an added token have no id.
 func (av *AddedVocabulary) TokenToId(token string, model Model) (retVal int, ok bool) {
 
 	retVal, ok = av.addedTokenMap[token]
 	if !ok {
-		return model.TokenToId(token)
+		retVal, ok = model.TokenToId(token)
+		if tok, isAdded := av.addedTokenMapR[retVal]; ok && isAdded && tok != token {
+			return 0, false
+		}
 	}
 
 	return retVal, ok
 }
 
 // Get the token matching the given id if it exists
+//
+// NOTE. Model ids whose token has been given another id have no token.
 func (av *AddedVocabulary) IdToToken(id int, model Model) (retVal string, ok bool) {
-	retVal, ok = av.addedTokenMapR[id]
-	if !ok {
-		return model.IdToToken(id)
+	return av.idToken(id, model)
+}
+
+// VacatedIds returns the ids of the model tokens that have been given another
+// id, and that are not assigned to an added token.
+func (av *AddedVocabulary) VacatedIds() []int {
+	var ids []int
+	for id := range av.vacatedIds {
+		if _, ok := av.addedTokenMapR[id]; !ok {
+			ids = append(ids, id)
+		}
 	}
+	sort.Ints(ids)
 
-	return retVal, ok
+	return ids
 }
 
 // Check if a token is a special token
@@ -288,6 +419,7 @@ func (av *AddedVocabulary) AddSpecialTokens(tokens []AddedToken, model Model, no
 func (av *AddedVocabulary) AddTokens(tokens []AddedToken, model Model, normalizer normalizer.Normalizer) (retVal int) {
 
 	ignored := 0
+	nextId := av.nextId(model)
 	for _, token := range tokens {
 		if token.Content == "" {
 			ignored++
@@ -299,7 +431,8 @@ func (av *AddedVocabulary) AddTokens(tokens []AddedToken, model Model, normalize
 			ignored++
 			id = i
 		} else {
-			id = model.GetVocabSize() + len(av.addedTokenMap)
+			id = nextId
+			nextId++
 			av.addedTokenMap[token.Content] = id
 
 			if _, ok := av.specialTokensSet[token.Content]; !ok {
@@ -317,46 +450,299 @@ func (av *AddedVocabulary) AddTokens(tokens []AddedToken, model Model, normalize
 	return len(tokens) - ignored
 }
 
+// nextId returns the id of a new added token, after the model vocabulary and
+// all the added tokens.
+func (av *AddedVocabulary) nextId(model Model) int {
+	id := model.GetVocabSize()
+	for i := range av.addedTokenMapR {
+		if i >= id {
+			id = i + 1
+		}
+	}
+
+	return id
+}
+
+// AddTokensWithIds adds some tokens to the vocabulary with the given ids.
+//
+// An id can be assigned to a token that is already in the vocabulary, to change its id,
+// or an id already in use, to repurpose it (e.g. a `[unused5]` or `<|reserved_special_token_3|>`
+// slot): the token previously at this id, model or added token, is replaced and no longer
+// matched nor converted to an id. `Special` sets whether the token is special, as
+// `AddSpecialTokens` does.
+//
+// It returns an error and leaves the vocabulary unchanged if a token is empty, an id is
+// negative or the same id is given twice.
+func (av *AddedVocabulary) AddTokensWithIds(tokens []AddedTokenWithId, model Model, normalizer normalizer.Normalizer) error {
+	ids := make(map[int]bool, len(tokens))
+	for _, t := range tokens {
+		switch {
+		case t.Token.Content == "":
+			return fmt.Errorf("AddedVocabulary.AddTokensWithIds() failed: empty token with id %d", t.Id)
+		case t.Id < 0:
+			return fmt.Errorf("AddedVocabulary.AddTokensWithIds() failed: negative id %d for token %q", t.Id, t.Token.Content)
+		case ids[t.Id]:
+			return fmt.Errorf("AddedVocabulary.AddTokensWithIds() failed: id %d is given twice", t.Id)
+		}
+		ids[t.Id] = true
+	}
+
+	for _, t := range tokens {
+		av.addTokenWithId(t, model, true)
+	}
+
+	av.refreshAddedTokens(model, normalizer)
+
+	return nil
+}
+
+// addTokenWithId adds a token with the given id, without refreshing the matching
+// sets. Tokens of patterns are not listed, i.e. not matched by the automaton.
+func (av *AddedVocabulary) addTokenWithId(t AddedTokenWithId, model Model, listed bool) {
+	content := t.Token.Content
+
+	// Replace the token at this id, and the previous id of this token
+	if prev, ok := av.idToken(t.Id, model); ok && prev != content {
+		av.removeToken(prev, model)
+	}
+	av.removeToken(content, model)
+
+	// A model token given another id leaves its model id
+	if av.vacatedIds == nil {
+		av.vacatedIds = make(map[int]bool)
+	}
+	if modelId, ok := model.TokenToId(content); ok {
+		if modelId == t.Id {
+			delete(av.vacatedIds, modelId)
+		} else {
+			av.vacatedIds[modelId] = true
+		}
+	}
+
+	av.addedTokenMap[content] = t.Id
+	av.addedTokenMapR[t.Id] = content
+	if t.Special {
+		av.specialTokensSet[content] = true
+	}
+	switch {
+	case !listed:
+	case t.Special:
+		av.specialTokens = append(av.specialTokens, t.Token)
+	default:
+		av.addedTokens = append(av.addedTokens, t.Token)
+	}
+}
+
+// AddTokenPatterns adds families of tokens defined by patterns to the vocabulary.
+// Their tokens, with ids from `BaseId` to `BaseId+Count-1`, replace the tokens
+// previously at these ids, as with `AddTokensWithIds`, but they are found in texts
+// by the pattern instead of one by one. A token of a family that is removed or
+// given another id is no longer found by the pattern. All the tokens of the families
+// are registered in the vocabulary, so memory grows with their `Count`.
+//
+// It returns an error and leaves the vocabulary unchanged if a pattern is invalid.
+func (av *AddedVocabulary) AddTokenPatterns(patterns []AddedTokenPattern, model Model, normalizer normalizer.Normalizer) error {
+	for _, p := range patterns {
+		if _, err := p.compile(); err != nil {
+			return fmt.Errorf("AddedVocabulary.AddTokenPatterns() failed: %w", err)
+		}
+	}
+
+	for _, p := range patterns {
+		for _, t := range p.tokens() {
+			av.addTokenWithId(t, model, false)
+		}
+		av.patterns = append(av.patterns, p)
+	}
+
+	av.refreshAddedTokens(model, normalizer)
+
+	return nil
+}
+
+// AddedTokens returns all the added tokens, including the tokens of patterns,
+// with their ids, sorted by id. E.g. to serialize them as `added_tokens`.
+func (av *AddedVocabulary) AddedTokens(model Model) []AddedTokenWithId {
+	var tokens []AddedTokenWithId
+	seen := make(map[string]bool)
+	add := func(tok AddedToken) {
+		id, ok := av.TokenToId(tok.Content, model)
+		if !ok || seen[tok.Content] {
+			return
+		}
+		seen[tok.Content] = true
+		tokens = append(tokens, AddedTokenWithId{Id: id, Special: av.specialTokensSet[tok.Content], Token: tok})
+	}
+
+	for _, tok := range av.specialTokens {
+		add(tok)
+	}
+	for _, tok := range av.addedTokens {
+		add(tok)
+	}
+	for _, p := range av.patterns {
+		for n := 0; n < p.Count; n++ {
+			if tok := p.token(n); av.addedTokenMap[tok.Content] == p.BaseId+n {
+				add(tok)
+			}
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Id < tokens[j].Id })
+
+	return tokens
+}
+
+// RemoveTokens removes some tokens from the vocabulary. The ids of the other tokens
+// don't change. Tokens of the model vocabulary are only unmarked as special, and
+// go back to their model id if they had been given another id.
+// It returns the number of removed tokens.
+func (av *AddedVocabulary) RemoveTokens(tokens []string, model Model, normalizer normalizer.Normalizer) (retVal int) {
+	for _, token := range tokens {
+		if av.removeToken(token, model) {
+			retVal++
+		}
+	}
+
+	av.refreshAddedTokens(model, normalizer)
+
+	return retVal
+}
+
+// UpdateToken replaces the options (`SingleWord`, `LStrip`, `RStrip`, `Normalized`) of the
+// added token with the same content, and sets whether it is special. Its id doesn't change.
+func (av *AddedVocabulary) UpdateToken(token AddedToken, special bool, model Model, normalizer normalizer.Normalizer) error {
+	content := token.Content
+	if _, ok := av.addedTokenMap[content]; !ok && !av.specialTokensSet[content] {
+		return fmt.Errorf("AddedVocabulary.UpdateToken() failed: %q is not an added token", content)
+	}
+
+	av.specialTokens = withoutToken(av.specialTokens, content)
+	av.addedTokens = withoutToken(av.addedTokens, content)
+	delete(av.specialTokensSet, content)
+	if special {
+		av.specialTokens = append(av.specialTokens, token)
+		av.specialTokensSet[content] = true
+	} else if _, ok := av.addedTokenMap[content]; ok {
+		av.addedTokens = append(av.addedTokens, token)
+	}
+
+	av.refreshAddedTokens(model, normalizer)
+
+	return nil
+}
+
+// idToken returns the token at the given id.
+func (av *AddedVocabulary) idToken(id int, model Model) (string, bool) {
+	if tok, ok := av.addedTokenMapR[id]; ok {
+		return tok, true
+	}
+	if av.vacatedIds[id] {
+		return "", false
+	}
+
+	return model.IdToToken(id)
+}
+
+// removeToken removes a token without refreshing the matching sets. It
+// reports whether the token was in the added vocabulary.
+func (av *AddedVocabulary) removeToken(token string, model Model) bool {
+	found := av.specialTokensSet[token]
+	if id, ok := av.addedTokenMap[token]; ok {
+		found = true
+		delete(av.addedTokenMap, token)
+		if av.addedTokenMapR[id] == token {
+			delete(av.addedTokenMapR, id)
+		}
+	} else if id, ok := model.TokenToId(token); ok && av.addedTokenMapR[id] == token {
+		delete(av.addedTokenMapR, id)
+	}
+	if id, ok := model.TokenToId(token); ok {
+		delete(av.vacatedIds, id)
+	}
+
+	delete(av.specialTokensSet, token)
+	av.specialTokens = withoutToken(av.specialTokens, token)
+	av.addedTokens = withoutToken(av.addedTokens, token)
+
+	return found
+}
+
+// withoutToken returns a copy of tokens without the given content, or tokens
+// if it doesn't contain it.
+func withoutToken(tokens []AddedToken, content string) []AddedToken {
+	found := false
+	for _, t := range tokens {
+		if t.Content == content {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return tokens
+	}
+
+	kept := make([]AddedToken, 0, len(tokens))
+	for _, t := range tokens {
+		if t.Content != content {
+			kept = append(kept, t)
+		}
+	}
+
+	return kept
+}
+
 type tokenId struct {
 	token AddedToken
 	id    int
 }
 
-// refreshAddedTokens reconstructs our internal RegexSet when new tokens are added to the vocabulary.
+// refreshAddedTokens reconstructs our internal matching sets when new tokens are added to the vocabulary.
 //
-// NOTE. We keep two different regular expression sets, one that will take care of matching against the
+// NOTE. We keep two different matching sets, one that will take care of matching against the
 // non-normalized string, and one matching against the normalized one.
-func (av *AddedVocabulary) refreshAddedTokens(model Model, normalizer normalizer.Normalizer) {
-	var normIds, nnormIds []int
+func (av *AddedVocabulary) refreshAddedTokens(model Model, n normalizer.Normalizer) {
+	var norm, nnorm matchingSet
 	var normPatterns, nnormPatterns []string
-	tokens := append(av.specialTokens, av.addedTokens...)
+	tokens := append(append([]AddedToken{}, av.specialTokens...), av.addedTokens...)
 	for _, token := range tokens {
 		id, ok := av.TokenToId(token.Content, model)
 		if !ok {
 			log.Fatalf("Missing additional token.\n")
 		}
 
-		pattern := token.GetPattern(normalizer)
 		if token.Normalized {
-			normIds = append(normIds, id)
-			normPatterns = append(normPatterns, pattern)
+			norm.ids = append(norm.ids, id)
+			norm.tokens = append(norm.tokens, token)
+			normPatterns = append(normPatterns, token.normalizedContent(n))
 		} else {
-			nnormIds = append(nnormIds, id)
-			nnormPatterns = append(nnormPatterns, pattern)
+			nnorm.ids = append(nnorm.ids, id)
+			nnorm.tokens = append(nnorm.tokens, token)
+			nnormPatterns = append(nnormPatterns, token.Content)
 		}
 	}
 
-	normSet, err := regexpset.NewRegexpSet(normPatterns)
-	if err != nil {
-		log.Fatal(err)
+	for _, p := range av.patterns {
+		compiled, err := p.compile()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if p.Token.Normalized {
+			norm.patterns = append(norm.patterns, compiled)
+		} else {
+			nnorm.patterns = append(nnorm.patterns, compiled)
+		}
 	}
-	nnormSet, err := regexpset.NewRegexpSet(nnormPatterns)
-	if err != nil {
-		log.Fatal(err)
+
+	if len(normPatterns) > 0 {
+		norm.ac = newAhoCorasick(normPatterns)
+	}
+	if len(nnormPatterns) > 0 {
+		nnorm.ac = newAhoCorasick(nnormPatterns)
 	}
 
-	av.splitNormalizedRe = matchingSet{*normSet, normIds}
-	av.splitRe = matchingSet{*nnormSet, nnormIds}
+	av.splitNormalizedRe = norm
+	av.splitRe = nnorm
 }
 
 type idOffsets struct {
@@ -364,96 +750,64 @@ type idOffsets struct {
 	offsets []int
 }
 
-// helper functions to sort idOffsets
-// By implement sort interface of package sort
-
-// byStart sort by offset.Start
-type byStart []idOffsets
-
-func (s byStart) Len() int           { return len(s) }
-func (s byStart) Less(i, j int) bool { return s[i].offsets[0] < s[j].offsets[0] }
-func (s byStart) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
-
-// byId sort by id
-type byId []idOffsets
-
-func (bi byId) Len() int           { return len(bi) }
-func (bi byId) Less(i, j int) bool { return bi[i].id < bi[j].id }
-func (bi byId) Swap(i, j int)      { bi[i], bi[j] = bi[j], bi[i] }
-
 // findMatches finds any AddedToken in the given sentence, using the provided MatchingSet.
 // This method returns a list "splits", each of them being a pair of Offsets
 // and an optional ID if it is an AddedToken. The list of splits cover the entire input string.
-func (av *AddedVocabulary) findMatches(sentence string, splitRe matchingSet) (retVal []idOffsets) {
+//
+// Tokens are matched leftmost-longest: the match starting first wins, and the longest
+// one among those starting at the same position. A `SingleWord` match is discarded if
+// it is preceded or followed by a word character. `LStrip` and `RStrip` matches are
+// extended over the whitespaces on their left and right.
+//
+// If keep is not nil, matches of tokens it doesn't keep are left as text.
+func (av *AddedVocabulary) findMatches(sentence string, splitRe matchingSet, keep func(token AddedToken) bool) (retVal []idOffsets) {
 
 	if len(sentence) == 0 {
 		return []idOffsets{{-1, []int{0, 0}}}
 	}
 
-	matches := splitRe.regexSet.Matches(sentence).Matches()
-	var ioPairs []idOffsets
-
-	for _, idx := range matches {
-		r := regexp.MustCompile(splitRe.regexSet.Patterns()[idx])
-		locs := r.FindAllStringIndex(sentence, -1)
-		for _, loc := range locs {
-			id := idx
-			ioPair := idOffsets{id: id, offsets: []int{loc[0], loc[1]}}
-			ioPairs = append(ioPairs, ioPair)
-		}
-	}
-
-	// Sort id-offsets by start then by pattern id
-	sort.Sort(byStart(ioPairs))
-	sort.Sort(byId(ioPairs))
-
-	// Select the matches, if they overlap, keep them
 	var (
-		i              int         = 0
-		currentOffsets int         = 0
-		splits         []idOffsets = make([]idOffsets, 0)
+		startOffset int = 0
+		finalSplits []idOffsets
 	)
 
-	for i < len(ioPairs) {
-		ioPair := ioPairs[i]
+	for _, m := range splitRe.findAll(sentence, av.addedTokenMap) {
+		token := m.token
+		start, stop := m.start, m.end
 
-		// current match is before the current offset, skip it
-		if ioPair.offsets[0] < currentOffsets {
-			i++
+		if keep != nil && !keep(token) {
 			continue
 		}
 
-		// Find out whether having overlapping neighbours.
-		// If so, keep the one with lowest Idx. All other will be skipped
-		// because `currentOffsets` will have been increased.
-		if i+1 < len(ioPairs) {
-			overlapPairs := ioPairs[i:]
-			sort.Sort(byId(overlapPairs))
-			lowestPair := overlapPairs[0] // lowest Id one
-			splits = append(splits, lowestPair)
-			currentOffsets = ioPair.offsets[1]
-			i++
+		// current match overlaps whitespaces stripped by the previous one, skip it
+		if start < startOffset {
 			continue
 		}
 
-		// Not found overlap neighbours. Just apply itself
-		splits = append(splits, ioPair)
-		currentOffsets = ioPair.offsets[1]
-		i++
-	}
+		if token.SingleWord {
+			before, _ := utf8.DecodeLastRuneInString(sentence[:start])
+			after, _ := utf8.DecodeRuneInString(sentence[stop:])
+			if (start > 0 && isWordRune(before)) || (stop < len(sentence) && isWordRune(after)) {
+				continue
+			}
+		}
 
-	// Also, insert the splits in-between added tokens, to split the entire string
-	var (
-		startOffset int = 0
-		finalSplits []idOffsets
-	)
+		if token.LStrip {
+			start = len(strings.TrimRightFunc(sentence[:start], unicode.IsSpace))
+			if start < startOffset {
+				start = startOffset
+			}
+		}
+		if token.RStrip {
+			stop = len(sentence) - len(strings.TrimLeftFunc(sentence[stop:], unicode.IsSpace))
+		}
 
-	for _, ioPair := range splits {
-		if startOffset < ioPair.offsets[0] {
-			finalSplits = append(finalSplits, idOffsets{-1, []int{startOffset, ioPair.offsets[0]}})
+		// Also, insert the splits in-between added tokens, to split the entire string
+		if startOffset < start {
+			finalSplits = append(finalSplits, idOffsets{-1, []int{startOffset, start}})
 		}
-		finalSplits = append(finalSplits, idOffsets{splitRe.ids[ioPair.id], ioPair.offsets})
-		startOffset = ioPair.offsets[1]
+		finalSplits = append(finalSplits, idOffsets{m.id, []int{start, stop}})
+		startOffset = stop
 	}
 
 	totalByteLen := len(sentence)
@@ -473,9 +827,9 @@ type SplitIdx struct {
 // the list of corresponding IDs.
 //
 // NOTE.The list of IDs have the exact same number of elements as the Iterator.
-func (av *AddedVocabulary) splitWithIndices(sentence *normalizer.NormalizedString, splitRe matchingSet) []SplitIdx {
+func (av *AddedVocabulary) splitWithIndices(sentence *normalizer.NormalizedString, splitRe matchingSet, keep func(token AddedToken) bool) []SplitIdx {
 
-	ioPairs := av.findMatches(sentence.GetNormalized(), splitRe)
+	ioPairs := av.findMatches(sentence.GetNormalized(), splitRe, keep)
 
 	var splits []SplitIdx
 
@@ -501,12 +855,40 @@ func (av *AddedVocabulary) splitWithIndices(sentence *normalizer.NormalizedStrin
 // input sentence `I read a book Yesterday`, if the normalizer is supposed to lowercase
 // everything, we expect a match.
 func (av *AddedVocabulary) ExtractAndNormalize(sequence string, n normalizer.Normalizer) *PreTokenizedString {
+	pretokenized, _ := av.extractAndNormalize(sequence, n, nil)
+	return pretokenized
+}
+
+// specialFilter decides whether a special token found in a text is extracted
+// (true) or left as ordinary text (false). An error stops the extraction.
+type specialFilter func(token string) (bool, error)
+
+// extractAndNormalize is ExtractAndNormalize, extracting only the special tokens
+// kept by the optional filter.
+func (av *AddedVocabulary) extractAndNormalize(sequence string, n normalizer.Normalizer, filter specialFilter) (*PreTokenizedString, error) {
+	var (
+		err  error
+		keep func(token AddedToken) bool
+	)
+	if filter != nil {
+		keep = func(token AddedToken) bool {
+			if err != nil {
+				return false
+			}
+			if !av.specialTokensSet[token.Content] {
+				return true
+			}
+			var ok bool
+			ok, err = filter(token.Content)
+			return ok && err == nil
+		}
+	}
 
 	pretokenized := NewPreTokenizedString(sequence)
 
 	// 1. Extract all non-normalized tokens from the non-normalized string
 	pretok1 := pretokenized.Split(func(idx int, seq *normalizer.NormalizedString) []SplitIdx {
-		return av.splitWithIndices(seq, av.splitRe)
+		return av.splitWithIndices(seq, av.splitRe, keep)
 	})
 
 	// 2. Extract the normalized tokens from the normalized pieces of the string
@@ -519,10 +901,10 @@ func (av *AddedVocabulary) ExtractAndNormalize(sequence string, n normalizer.Nor
 				log.Fatal(err)
 			}
 		}
-		return av.splitWithIndices(newSeq, av.splitNormalizedRe)
+		return av.splitWithIndices(newSeq, av.splitNormalizedRe, keep)
 	})
 
-	return pretok2
+	return pretok2, err
 }
 
 type AddedTokenWithId struct {
@@ -531,6 +913,19 @@ type AddedTokenWithId struct {
 	Token   AddedToken // the target AddedToken
 }
 
+// TokenConfig returns the `tokenizer.json` entry of the token.
+func (t AddedTokenWithId) TokenConfig() TokenConfig {
+	return TokenConfig{
+		Id:         int64(t.Id),
+		Content:    t.Token.Content,
+		SingleWord: t.Token.SingleWord,
+		Lstrip:     t.Token.LStrip,
+		Rstrip:     t.Token.RStrip,
+		Normalized: t.Token.Normalized,
+		Special:    t.Special,
+	}
+}
+
 // Implement Serialize interface for AddedVocabular:
 // =================================================
 
@@ -1,8 +1,9 @@
 package tokenizer_test
 
 import (
-	// "fmt"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/sugarme/tokenizer"
@@ -228,3 +229,452 @@ func TestOptionUseCases(t *testing.T) {
 		t.Errorf("Got %+v\n", got)
 	}
 }
+
+type splitTokens struct {
+	value string
+	ids   []int
+}
+
+func extractSplits(vocab *tokenizer.AddedVocabulary, sequence string, n normalizer.Normalizer) []splitTokens {
+	var got []splitTokens
+	result := vocab.ExtractAndNormalize(sequence, n)
+	for _, pretok := range result.GetSplits(normalizer.OriginalTarget, tokenizer.Byte) {
+		var ids []int
+		for _, tok := range pretok.Tokens {
+			ids = append(ids, tok.Id)
+		}
+		got = append(got, splitTokens{pretok.Value, ids})
+	}
+
+	return got
+}
+
+func TestExtractLeftmostLongest(t *testing.T) {
+	model := newModelMock([]string{}, []int{})
+	vocab := tokenizer.NewAddedVocabulary()
+	vocab.AddTokens([]tokenizer.AddedToken{
+		tokenizer.NewAddedToken("<tool>", false),
+		tokenizer.NewAddedToken("<tool_call>", false),
+		tokenizer.NewAddedToken("call>x", false),
+		tokenizer.NewAddedToken("ab", false),
+		tokenizer.NewAddedToken("bcd", false),
+	}, model, nil)
+
+	got := extractSplits(&vocab, "<tool_call>x <tool> abcd", nil)
+	want := []splitTokens{
+		// Longest match at the leftmost position
+		{"<tool_call>", []int{1}},
+		{"x ", nil},
+		{"<tool>", []int{0}},
+		{" ", nil},
+		// Leftmost match wins over a longer one starting later
+		{"ab", []int{3}},
+		{"cd", nil},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+}
+
+func TestExtractSingleWordAndStrip(t *testing.T) {
+	model := newModelMock([]string{}, []int{})
+	vocab := tokenizer.NewAddedVocabulary()
+	vocab.AddTokens([]tokenizer.AddedToken{
+		tokenizer.NewAddedToken("at", false).SetSingleWord(true),
+		tokenizer.NewAddedToken("<mask>", true).SetLStrip(true),
+		tokenizer.NewAddedToken("<eos>", true).SetRStrip(true),
+	}, model, nil)
+
+	got := extractSplits(&vocab, "cat at_ at, a  <mask><eos>  end", nil)
+	want := []splitTokens{
+		// `at` inside `cat` and before `_` is not a single word
+		{"cat at_ ", nil},
+		{"at", []int{0}},
+		{", a", nil},
+		// Whitespaces on the left of `<mask>` and the right of `<eos>` are stripped
+		{"  <mask>", []int{1}},
+		{"<eos>  ", []int{2}},
+		{"end", nil},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+}
+
+func TestExtractNormalized(t *testing.T) {
+	model := newModelMock([]string{}, []int{})
+	vocab := tokenizer.NewAddedVocabulary()
+	n := normalizer.Lowercase()
+	vocab.AddTokens([]tokenizer.AddedToken{
+		// Normalized tokens match their normalized content in the normalized text
+		tokenizer.NewAddedToken("Yesterday", false),
+		// Others match their content in the original text
+		tokenizer.NewAddedToken("[UNUSED]", true),
+	}, model, n)
+
+	got := extractSplits(&vocab, "YESTERDAY [UNUSED] [unused]", n)
+	want := []splitTokens{
+		{"yesterday", []int{0}},
+		{" ", nil},
+		{"[UNUSED]", []int{1}},
+		{" [unused]", nil},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+}
+
+func TestRemoveTokens(t *testing.T) {
+	model := newModelMock([]string{"test", "tost"}, []int{0, 1})
+	vocab := tokenizer.NewAddedVocabulary()
+	vocab.AddTokens([]tokenizer.AddedToken{
+		tokenizer.NewAddedToken("added_1", false),
+		tokenizer.NewAddedToken("added_2", false),
+	}, model, nil)
+	vocab.AddSpecialTokens([]tokenizer.AddedToken{
+		tokenizer.NewAddedToken("[SEP]", true),
+		tokenizer.NewAddedToken("test", true),
+	}, model, nil)
+
+	got := vocab.RemoveTokens([]string{"added_1", "test", "unknown"}, model, nil)
+	if got != 2 {
+		t.Errorf("Want 2 removed tokens, got %v\n", got)
+	}
+
+	wantVocab := map[string]int{"added_2": 3, "[SEP]": 4}
+	if !reflect.DeepEqual(wantVocab, vocab.GetVocab()) {
+		t.Errorf("Want %v\n", wantVocab)
+		t.Errorf("Got %v\n", vocab.GetVocab())
+	}
+	if _, ok := vocab.IdToToken(2, model); ok {
+		t.Errorf("Want no token at id 2\n")
+	}
+	if vocab.IsSpecialToken("test") {
+		t.Errorf("Want 'test' not special anymore\n")
+	}
+
+	// Ids are not reused
+	vocab.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("added_3", false)}, model, nil)
+	if id, _ := vocab.TokenToId("added_3", model); id != 5 {
+		t.Errorf("Want id 5, got %v\n", id)
+	}
+
+	gotSplits := extractSplits(&vocab, "added_1 added_2 test [SEP]", nil)
+	wantSplits := []splitTokens{
+		{"added_1 ", nil},
+		{"added_2", []int{3}},
+		{" test ", nil},
+		{"[SEP]", []int{4}},
+	}
+	if !reflect.DeepEqual(wantSplits, gotSplits) {
+		t.Errorf("Want %+v\n", wantSplits)
+		t.Errorf("Got %+v\n", gotSplits)
+	}
+}
+
+func TestUpdateToken(t *testing.T) {
+	model := newModelMock([]string{}, []int{})
+	vocab := tokenizer.NewAddedVocabulary()
+	vocab.AddTokens([]tokenizer.AddedToken{
+		tokenizer.NewAddedToken("<mask>", false),
+	}, model, nil)
+
+	err := vocab.UpdateToken(tokenizer.NewAddedToken("<mask>", true).SetLStrip(true), true, model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vocab.IsSpecialToken("<mask>") {
+		t.Errorf("Want '<mask>' special\n")
+	}
+
+	got := extractSplits(&vocab, "a <mask>", nil)
+	want := []splitTokens{
+		{"a", nil},
+		{" <mask>", []int{0}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+
+	if err := vocab.UpdateToken(tokenizer.NewAddedToken("<unk>", true), true, model, nil); err == nil {
+		t.Errorf("Want an error updating a token not in the vocabulary\n")
+	}
+}
+
+func TestAddTokensWithIds(t *testing.T) {
+	model := newModelMock([]string{"hello", "[unused5]"}, []int{0, 1})
+	vocab := tokenizer.NewAddedVocabulary()
+	vocab.AddSpecialTokens([]tokenizer.AddedToken{
+		tokenizer.NewAddedToken("<|reserved_0|>", true),
+		tokenizer.NewAddedToken("<|reserved_1|>", true),
+	}, model, nil)
+
+	err := vocab.AddTokensWithIds([]tokenizer.AddedTokenWithId{
+		// Repurposes a model slot
+		{Id: 1, Special: true, Token: tokenizer.NewAddedToken("<tool>", true)},
+		// Repurposes an added slot
+		{Id: 3, Special: false, Token: tokenizer.NewAddedToken("<think>", false)},
+		// Re-ids an added token
+		{Id: 10, Special: true, Token: tokenizer.NewAddedToken("<|reserved_0|>", true)},
+	}, model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantVocab := map[string]int{"<tool>": 1, "<think>": 3, "<|reserved_0|>": 10}
+	if !reflect.DeepEqual(wantVocab, vocab.GetVocab()) {
+		t.Errorf("Want %v\n", wantVocab)
+		t.Errorf("Got %v\n", vocab.GetVocab())
+	}
+
+	for id, want := range map[int]string{0: "hello", 1: "<tool>", 3: "<think>", 10: "<|reserved_0|>"} {
+		if got, _ := vocab.IdToToken(id, model); got != want {
+			t.Errorf("IdToToken(%v): want %q, got %q\n", id, want, got)
+		}
+	}
+	for _, tok := range []string{"[unused5]", "<|reserved_1|>"} {
+		if _, ok := vocab.TokenToId(tok, model); ok {
+			t.Errorf("Want no id for replaced token %q\n", tok)
+		}
+	}
+	if vocab.IsSpecialToken("<|reserved_1|>") || vocab.IsSpecialToken("<think>") || !vocab.IsSpecialToken("<tool>") {
+		t.Errorf("Wrong special tokens\n")
+	}
+
+	got := extractSplits(&vocab, "<tool><think><|reserved_0|><|reserved_1|>", nil)
+	want := []splitTokens{
+		{"<tool>", []int{1}},
+		{"<think>", []int{3}},
+		{"<|reserved_0|>", []int{10}},
+		{"<|reserved_1|>", nil},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+
+	// Invalid ids leave the vocabulary unchanged
+	err = vocab.AddTokensWithIds([]tokenizer.AddedTokenWithId{
+		{Id: 20, Token: tokenizer.NewAddedToken("a", false)},
+		{Id: 20, Token: tokenizer.NewAddedToken("b", false)},
+	}, model, nil)
+	if err == nil || vocab.Len() != 3 {
+		t.Errorf("Want an error for a duplicate id and no change, got %v, %v tokens\n", err, vocab.Len())
+	}
+}
+
+func TestTokenizer_AddTokensWithIds(t *testing.T) {
+	tk := tokenizer.NewTokenizer(newModelMock([]string{"hello", "[unused5]"}, []int{0, 1}))
+	tk.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("world", false)})
+
+	err := tk.AddTokensWithIds([]tokenizer.AddedTokenWithId{
+		{Id: 1, Special: true, Token: tokenizer.NewAddedToken("<tool>", true)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantVocab := map[string]int{"hello": 0, "<tool>": 1, "world": 2}
+	if got := tk.GetVocab(true); !reflect.DeepEqual(wantVocab, got) {
+		t.Errorf("Want %v\n", wantVocab)
+		t.Errorf("Got %v\n", got)
+	}
+	if got := tk.GetVocabSize(true); got != 3 {
+		t.Errorf("Want vocab size 3, got %v\n", got)
+	}
+	if got, _ := tk.IdToToken(1); got != "<tool>" {
+		t.Errorf("Want '<tool>', got %q\n", got)
+	}
+
+	if got := tk.RemoveTokens([]string{"<tool>"}); got != 1 {
+		t.Errorf("Want 1 removed token, got %v\n", got)
+	}
+	if got, _ := tk.IdToToken(1); got != "[unused5]" {
+		t.Errorf("Want '[unused5]' back, got %q\n", got)
+	}
+}
+
+func TestTokenizer_AddTokensWithIds_MoveModelToken(t *testing.T) {
+	tk := tokenizer.NewTokenizer(newModelMock([]string{"[UNK]", "hello", "world"}, []int{0, 1, 2}))
+
+	err := tk.AddTokensWithIds([]tokenizer.AddedTokenWithId{
+		{Id: 10, Token: tokenizer.NewAddedToken("hello", false)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := tk.TokenToId("hello"); got != 10 {
+		t.Errorf("Want id 10, got %v\n", got)
+	}
+	if got, ok := tk.IdToToken(1); ok {
+		t.Errorf("Want no token at the previous id, got %q\n", got)
+	}
+	if got := tk.Decode([]int{1, 10}, false); got != "hello" {
+		t.Errorf("Want 'hello', got %q\n", got)
+	}
+	wantVocab := map[string]int{"[UNK]": 0, "hello": 10, "world": 2}
+	if got := tk.GetVocab(true); !reflect.DeepEqual(wantVocab, got) {
+		t.Errorf("Want %v\n", wantVocab)
+		t.Errorf("Got %v\n", got)
+	}
+	if got := tk.GetVocabSize(true); got != len(wantVocab) {
+		t.Errorf("Want vocab size %v, got %v\n", len(wantVocab), got)
+	}
+
+	// Removing the added token puts the model token back at its model id
+	tk.RemoveTokens([]string{"hello"})
+	if got, _ := tk.IdToToken(1); got != "hello" {
+		t.Errorf("Want 'hello' back, got %q\n", got)
+	}
+	if got, _ := tk.TokenToId("hello"); got != 1 {
+		t.Errorf("Want id 1, got %v\n", got)
+	}
+	if got := tk.GetVocabSize(true); got != 3 {
+		t.Errorf("Want vocab size 3, got %v\n", got)
+	}
+}
+
+func TestAddTokenPatterns(t *testing.T) {
+	model := newModelMock([]string{"hello"}, []int{0})
+	vocab := tokenizer.NewAddedVocabulary()
+	vocab.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("id_", false)}, model, nil)
+
+	err := vocab.AddTokenPatterns([]tokenizer.AddedTokenPattern{
+		tokenizer.NewAddedTokenPattern("<extra_id_%d>", 10, 100, true, tokenizer.WithLStrip(true)),
+		tokenizer.NewAddedTokenPattern("<0x%02X>", 200, 256, true),
+	}, model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := extractSplits(&vocab, "id_ <extra_id_5><extra_id_100><0x0A><0x0a><extra_id_05> <extra_id_99>", nil)
+	want := []splitTokens{
+		{"id_", []int{1}},
+		// The pattern strips whitespaces on the left
+		{" <extra_id_5>", []int{15}},
+		// Out of range
+		{"<extra_", nil},
+		{"id_", []int{1}},
+		{"100>", nil},
+		{"<0x0A>", []int{210}},
+		// Not formatted like the tokens
+		{"<0x0a><extra_", nil},
+		{"id_", []int{1}},
+		{"05>", nil},
+		{" <extra_id_99>", []int{109}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+
+	if id, ok := vocab.TokenToId("<0xFF>", model); !ok || id != 455 {
+		t.Errorf("Want id 455, got %v, %v\n", id, ok)
+	}
+	if tok, _ := vocab.IdToToken(42, model); tok != "<extra_id_32>" {
+		t.Errorf("Want '<extra_id_32>', got %q\n", tok)
+	}
+	if !vocab.IsSpecialToken("<extra_id_0>") {
+		t.Errorf("Want '<extra_id_0>' special\n")
+	}
+
+	// Removed tokens are not matched anymore
+	vocab.RemoveTokens([]string{"<extra_id_99>"}, model, nil)
+	got = extractSplits(&vocab, "<extra_id_98><extra_id_99>", nil)
+	want = []splitTokens{
+		{"<extra_id_98>", []int{108}},
+		{"<extra_", nil},
+		{"id_", []int{1}},
+		{"99>", nil},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+
+	// Tokens of patterns are listed individually
+	tokens := vocab.AddedTokens(model)
+	if len(tokens) != 1+99+256 {
+		t.Fatalf("Want %v added tokens, got %v\n", 1+99+256, len(tokens))
+	}
+	wantFirst := tokenizer.TokenConfig{Id: 1, Content: "id_", Normalized: true}
+	if got := tokens[0].TokenConfig(); !reflect.DeepEqual(wantFirst, got) {
+		t.Errorf("Want %+v, got %+v\n", wantFirst, got)
+	}
+	wantSecond := tokenizer.TokenConfig{Id: 10, Content: "<extra_id_0>", Lstrip: true, Special: true}
+	if got := tokens[1].TokenConfig(); !reflect.DeepEqual(wantSecond, got) {
+		t.Errorf("Want %+v, got %+v\n", wantSecond, got)
+	}
+
+	// A user regexp matches more than the format, with the id of the number
+	vocab = tokenizer.NewAddedVocabulary()
+	byteTokens := tokenizer.NewAddedTokenPattern("<0x%02X>", 200, 256, true)
+	byteTokens.Regexp = `(?i)<0x([0-9a-f]{2})>`
+	if err := vocab.AddTokenPatterns([]tokenizer.AddedTokenPattern{byteTokens}, model, nil); err != nil {
+		t.Fatal(err)
+	}
+	got = extractSplits(&vocab, "<0x0a><0x0A><0xg0>", nil)
+	want = []splitTokens{
+		{"<0x0a>", []int{210}},
+		{"<0x0A>", []int{210}},
+		{"<0xg0>", nil},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %+v\n", want)
+		t.Errorf("Got %+v\n", got)
+	}
+	if tok, _ := vocab.IdToToken(210, model); tok != "<0x0A>" {
+		t.Errorf("Want '<0x0A>', got %q\n", tok)
+	}
+
+	// Invalid patterns
+	for _, p := range []tokenizer.AddedTokenPattern{
+		tokenizer.NewAddedTokenPattern("<extra_id>", 10, 100, true),
+		tokenizer.NewAddedTokenPattern("<%d_%d>", 10, 100, true),
+		tokenizer.NewAddedTokenPattern("<extra_id_%d>", 10, 0, true),
+	} {
+		if err := vocab.AddTokenPatterns([]tokenizer.AddedTokenPattern{p}, model, nil); err == nil {
+			t.Errorf("Want an error for pattern %+v\n", p)
+		}
+	}
+}
+
+func BenchmarkExtractAndNormalize(b *testing.B) {
+	for _, size := range []int{10, 1000, 10000} {
+		model := newModelMock([]string{}, []int{})
+		vocab := tokenizer.NewAddedVocabulary()
+		var toks []tokenizer.AddedToken
+		for i := 0; i < size; i++ {
+			toks = append(toks, tokenizer.NewAddedToken(fmt.Sprintf("<|reserved_%d|>", i), true))
+		}
+		vocab.AddSpecialTokens(toks, model, nil)
+
+		text := strings.Repeat("<|reserved_7|> Some text between the special tokens. ", 50)
+
+		b.Run(fmt.Sprintf("tokens=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				vocab.ExtractAndNormalize(text, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkAddTokens(b *testing.B) {
+	var toks []tokenizer.AddedToken
+	for i := 0; i < 10000; i++ {
+		toks = append(toks, tokenizer.NewAddedToken(fmt.Sprintf("<|reserved_%d|>", i), true))
+	}
+
+	for i := 0; i < b.N; i++ {
+		vocab := tokenizer.NewAddedVocabulary()
+		vocab.AddSpecialTokens(toks, newModelMock([]string{}, []int{}), nil)
+	}
+}
@@ -0,0 +1,158 @@
+package tokenizer
+
+// ahoCorasick is an Aho-Corasick automaton finding the leftmost-longest,
+// non-overlapping occurrences of a set of patterns in a text, in a single
+// pass whatever the number of patterns.
+type ahoCorasick struct {
+	nodes []acNode
+	// root transitions, 0 for no transition
+	root [256]int32
+	// byte length of each pattern
+	lens []int
+}
+
+type acEdge struct {
+	b  byte
+	to int32
+}
+
+type acNode struct {
+	edges []acEdge
+	// longest proper suffix of this node that is also a node
+	fail  int32
+	depth int32
+	// longest pattern which is a suffix of this node, -1 if none
+	match int32
+}
+
+// acMatch is an occurrence of pattern in text[start:end].
+type acMatch struct {
+	pattern int
+	start   int
+	end     int
+}
+
+// newAhoCorasick builds an automaton matching the given patterns. Empty
+// patterns never match. If several patterns are equal, the first one matches.
+func newAhoCorasick(patterns []string) *ahoCorasick {
+	ac := &ahoCorasick{
+		nodes: []acNode{{match: -1}},
+		lens:  make([]int, len(patterns)),
+	}
+
+	// 1. Trie of the patterns
+	for i, p := range patterns {
+		ac.lens[i] = len(p)
+		if len(p) == 0 {
+			continue
+		}
+		s := int32(0)
+		for j := 0; j < len(p); j++ {
+			next := ac.nodes[s].child(p[j])
+			if next < 0 {
+				next = int32(len(ac.nodes))
+				ac.nodes = append(ac.nodes, acNode{depth: ac.nodes[s].depth + 1, match: -1})
+				ac.nodes[s].edges = append(ac.nodes[s].edges, acEdge{p[j], next})
+			}
+			s = next
+		}
+		if ac.nodes[s].match < 0 {
+			ac.nodes[s].match = int32(i)
+		}
+	}
+
+	for _, e := range ac.nodes[0].edges {
+		ac.root[e.b] = e.to
+	}
+
+	// 2. Failure links, breadth first so that the failure node of a node is
+	// complete before the node itself.
+	queue := make([]int32, 0, len(ac.nodes))
+	for _, e := range ac.nodes[0].edges {
+		queue = append(queue, e.to)
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, e := range ac.nodes[s].edges {
+			fail := int32(0)
+			if s != 0 {
+				fail = ac.step(ac.nodes[s].fail, e.b)
+			}
+			next := &ac.nodes[e.to]
+			next.fail = fail
+			if next.match < 0 {
+				next.match = ac.nodes[fail].match
+			}
+			queue = append(queue, e.to)
+		}
+	}
+
+	return ac
+}
+
+// child returns the node reached from n with byte b, or -1.
+func (n *acNode) child(b byte) int32 {
+	for _, e := range n.edges {
+		if e.b == b {
+			return e.to
+		}
+	}
+
+	return -1
+}
+
+// step returns the node reached from s with byte b, following failure links.
+func (ac *ahoCorasick) step(s int32, b byte) int32 {
+	for s != 0 {
+		if next := ac.nodes[s].child(b); next >= 0 {
+			return next
+		}
+		s = ac.nodes[s].fail
+	}
+
+	return ac.root[b]
+}
+
+// findAll returns the non-overlapping matches in text, from left to right.
+// Among the matches starting at the leftmost position, the longest is
+// selected, and the search resumes at its end.
+func (ac *ahoCorasick) findAll(text string) []acMatch {
+	var matches []acMatch
+
+	pos := 0
+	for {
+		m, ok := ac.next(text, pos)
+		if !ok {
+			break
+		}
+		matches = append(matches, m)
+		pos = m.end
+	}
+
+	return matches
+}
+
+// next returns the leftmost-longest match starting at or after pos.
+func (ac *ahoCorasick) next(text string, pos int) (acMatch, bool) {
+	best := acMatch{pattern: -1}
+	s := int32(0)
+	for j := pos; j < len(text); j++ {
+		s = ac.step(s, text[j])
+		node := &ac.nodes[s]
+		// Later matches start after the current node, so they can
+		// neither start before nor extend the best match.
+		if best.pattern >= 0 && best.start < j+1-int(node.depth) {
+			break
+		}
+		if node.match < 0 {
+			continue
+		}
+		start := j + 1 - ac.lens[node.match]
+		if best.pattern < 0 || start <= best.start {
+			best = acMatch{pattern: int(node.match), start: start, end: j + 1}
+		}
+	}
+
+	return best, best.pattern >= 0
+}
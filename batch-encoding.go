@@ -0,0 +1,149 @@
+package tokenizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnpaddedBatch is returned when building a BatchEncoding from encodings of different lengths.
+var ErrUnpaddedBatch = errors.New("batch encoding: encodings must have the same length, padding should be enabled")
+
+// BatchEncoding is a batch of encodings of the same length, i.e. padded,
+// ready to be converted into row-major tensors of shape [BatchSize, SeqLen].
+//
+// Example:
+//
+//	batch, err := tk.EncodeBatchTensors(ctx, inputs, tokenizer.WithBatchAddSpecialTokens(true))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	tensors := batch.Int64()
+//	// tensors.InputIds, tensors.AttentionMask... with shape batch.Shape()
+type BatchEncoding struct {
+	Encodings []Encoding
+	BatchSize int
+	SeqLen    int
+}
+
+// BatchTensors holds the tensors of a BatchEncoding as contiguous row-major
+// buffers. Token `j` of row `i` is at index `i*SeqLen + j`.
+type BatchTensors[T int32 | int64] struct {
+	BatchSize         int
+	SeqLen            int
+	InputIds          []T
+	AttentionMask     []T
+	TypeIds           []T
+	SpecialTokensMask []T
+	// PositionIds counts non-padding tokens only, so that the first real token
+	// is at position 0 whatever the padding direction. Padding tokens get position 0.
+	PositionIds []T
+}
+
+// NewBatchEncoding creates a BatchEncoding from encodings of the same length.
+func NewBatchEncoding(encodings []Encoding) (*BatchEncoding, error) {
+	var seqLen int
+	for i, e := range encodings {
+		if i == 0 {
+			seqLen = e.Len()
+			continue
+		}
+		if e.Len() != seqLen {
+			return nil, fmt.Errorf("%w: encoding %d has length %d, want %d", ErrUnpaddedBatch, i, e.Len(), seqLen)
+		}
+	}
+
+	return &BatchEncoding{
+		Encodings: encodings,
+		BatchSize: len(encodings),
+		SeqLen:    seqLen,
+	}, nil
+}
+
+// Shape returns the shape of the tensors: [BatchSize, SeqLen].
+func (b *BatchEncoding) Shape() []int64 {
+	return []int64{int64(b.BatchSize), int64(b.SeqLen)}
+}
+
+// Int64 returns the tensors as newly allocated int64 buffers.
+func (b *BatchEncoding) Int64() *BatchTensors[int64] {
+	t := new(BatchTensors[int64])
+	FillBatchTensors(b, t)
+	return t
+}
+
+// Int32 returns the tensors as newly allocated int32 buffers.
+func (b *BatchEncoding) Int32() *BatchTensors[int32] {
+	t := new(BatchTensors[int32])
+	FillBatchTensors(b, t)
+	return t
+}
+
+// FillBatchTensors writes the tensors of the batch into `dst`. The buffers of
+// `dst` are reused if they are large enough, otherwise they are reallocated,
+// so the same `dst` can be used for many batches without allocating.
+func FillBatchTensors[T int32 | int64](b *BatchEncoding, dst *BatchTensors[T]) {
+	n := b.BatchSize * b.SeqLen
+
+	dst.BatchSize = b.BatchSize
+	dst.SeqLen = b.SeqLen
+	dst.InputIds = resize(dst.InputIds, n)
+	dst.AttentionMask = resize(dst.AttentionMask, n)
+	dst.TypeIds = resize(dst.TypeIds, n)
+	dst.SpecialTokensMask = resize(dst.SpecialTokensMask, n)
+	dst.PositionIds = resize(dst.PositionIds, n)
+
+	for i := range b.Encodings {
+		e := &b.Encodings[i]
+		row := i * b.SeqLen
+		position := 0
+		for j := 0; j < b.SeqLen; j++ {
+			k := row + j
+			dst.InputIds[k] = T(e.Ids[j])
+			dst.AttentionMask[k] = T(valueAt(e.AttentionMask, j, 1))
+			dst.TypeIds[k] = T(valueAt(e.TypeIds, j, 0))
+			dst.SpecialTokensMask[k] = T(valueAt(e.SpecialTokenMask, j, 0))
+
+			if dst.AttentionMask[k] == 0 {
+				dst.PositionIds[k] = 0
+				continue
+			}
+			dst.PositionIds[k] = T(position)
+			position++
+		}
+	}
+}
+
+// resize returns a slice of length n, reusing the given slice if possible.
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+
+	return s[:n]
+}
+
+// valueAt returns s[i], or the default value if s is too short (e.g. the
+// output has been stripped).
+func valueAt(s []int, i, defaultValue int) int {
+	if i < len(s) {
+		return s[i]
+	}
+
+	return defaultValue
+}
+
+// EncodeBatchTensors encodes all inputs like `EncodeBatchContext` and returns
+// them as a BatchEncoding. Padding must be enabled, either on the tokenizer or
+// with `WithEncodeOptions(WithPaddingParams(...))`, unless all inputs have the
+// same length.
+//
+// As a batch tensor can't have missing rows, any failing input fails the whole call.
+func (t *Tokenizer) EncodeBatchTensors(ctx context.Context, inputs []EncodeInput, opts ...BatchOption) (*BatchEncoding, error) {
+	encodings, err := t.EncodeBatchContext(ctx, inputs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBatchEncoding(encodings)
+}
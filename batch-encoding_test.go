@@ -0,0 +1,66 @@
+package tokenizer_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func TestEncodeBatchTensors(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	tk.WithPadding(&tokenizer.PaddingParams{
+		Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithBatchLongest()),
+		Direction: tokenizer.Left,
+		PadToken:  "[PAD]",
+	})
+
+	batch, err := tk.EncodeBatchTensors(context.Background(), batchInputs("hello", "hello world"), tokenizer.WithBatchAddSpecialTokens(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int64{2, 4}; !reflect.DeepEqual(want, batch.Shape()) {
+		t.Fatalf("want shape %v, got %v", want, batch.Shape())
+	}
+
+	tensors := batch.Int64()
+	if want := []int64{0, 101, 7592, 102, 101, 7592, 2088, 102}; !reflect.DeepEqual(want, tensors.InputIds) {
+		t.Errorf("want input ids %v, got %v", want, tensors.InputIds)
+	}
+	if want := []int64{0, 1, 1, 1, 1, 1, 1, 1}; !reflect.DeepEqual(want, tensors.AttentionMask) {
+		t.Errorf("want attention mask %v, got %v", want, tensors.AttentionMask)
+	}
+	if want := []int64{1, 1, 0, 1, 1, 0, 0, 1}; !reflect.DeepEqual(want, tensors.SpecialTokensMask) {
+		t.Errorf("want special tokens mask %v, got %v", want, tensors.SpecialTokensMask)
+	}
+	if want := []int64{0, 0, 1, 2, 0, 1, 2, 3}; !reflect.DeepEqual(want, tensors.PositionIds) {
+		t.Errorf("want position ids %v, got %v", want, tensors.PositionIds)
+	}
+
+	// Caller-provided buffers are reused
+	dst := &tokenizer.BatchTensors[int32]{InputIds: make([]int32, 0, 16)}
+	tokenizer.FillBatchTensors(batch, dst)
+	buf := &dst.InputIds[0]
+	allocs := testing.AllocsPerRun(10, func() {
+		tokenizer.FillBatchTensors(batch, dst)
+	})
+	if allocs != 0 || buf != &dst.InputIds[0] {
+		t.Errorf("buffers reallocated: %v allocs", allocs)
+	}
+	if !reflect.DeepEqual(dst, batch.Int32()) {
+		t.Errorf("want %v, got %v", batch.Int32(), dst)
+	}
+}
+
+func TestNewBatchEncoding_Unpadded(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	_, err := tk.EncodeBatchTensors(context.Background(), batchInputs("hello", "hello world"))
+	if !errors.Is(err, tokenizer.ErrUnpaddedBatch) {
+		t.Errorf("want ErrUnpaddedBatch, got %v", err)
+	}
+}
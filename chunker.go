@@ -0,0 +1,269 @@
+package tokenizer
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChunkBoundary is a kind of text boundary where a chunk can end.
+type ChunkBoundary int
+
+const (
+	// ParagraphBoundary is a blank line between two tokens.
+	ParagraphBoundary ChunkBoundary = iota
+	// SentenceBoundary is a line break, or a sentence-ending punctuation
+	// followed by whitespace or ending a CJK sentence.
+	SentenceBoundary
+	// WhitespaceBoundary is any whitespace between two tokens.
+	WhitespaceBoundary
+	// HardCut is any boundary between two tokens that doesn't split a character.
+	HardCut
+)
+
+// Chunk is a part of a text of at most `ChunkOptions.MaxTokens` tokens.
+type Chunk struct {
+	// Text is the substring of the original text, from the start of its first
+	// token to the end of its last token, without leading and trailing
+	// whitespaces (kept in tokens by Metaspace and byte-level tokenizers).
+	Text string
+	// Start and End are the char (rune) offsets of `Text` in the original text.
+	Start int
+	End   int
+	// Tokens is the number of tokens of the chunk, without special tokens.
+	Tokens int
+}
+
+// ChunkOptions holds the options of `Tokenizer.Chunk`.
+type ChunkOptions struct {
+	// Maximum number of tokens per chunk, without special tokens
+	MaxTokens int
+	// Number of tokens shared by consecutive chunks
+	Overlap int
+	// Boundaries where chunks may end, by order of preference.
+	// A hard cut is always the last resort.
+	Boundaries []ChunkBoundary
+}
+
+type ChunkOption func(o *ChunkOptions)
+
+// WithChunkOverlap sets the number of tokens shared by consecutive chunks.
+func WithChunkOverlap(n int) ChunkOption {
+	return func(o *ChunkOptions) {
+		o.Overlap = n
+	}
+}
+
+// WithChunkBoundaries sets the boundaries where chunks may end, by order of preference.
+func WithChunkBoundaries(boundaries ...ChunkBoundary) ChunkOption {
+	return func(o *ChunkOptions) {
+		o.Boundaries = boundaries
+	}
+}
+
+// DefaultChunkOptions returns options without overlap, preferring paragraph,
+// then sentence, then whitespace boundaries.
+func DefaultChunkOptions(maxTokens int) *ChunkOptions {
+	return &ChunkOptions{
+		MaxTokens:  maxTokens,
+		Overlap:    0,
+		Boundaries: []ChunkBoundary{ParagraphBoundary, SentenceBoundary, WhitespaceBoundary},
+	}
+}
+
+// Chunk splits the text into chunks of at most `maxTokens` tokens, using the
+// offsets of the tokens so that chunks are substrings of the original text.
+//
+// Each chunk ends at the furthest boundary of the most preferred kind that
+// fits in `maxTokens` tokens, and the next chunk starts `Overlap` tokens
+// before it. Chunks never split a character, even if the model splits it into
+// several tokens (e.g. byte-level BPE), so the overlap is shortened when it
+// would start in the middle of a character.
+//
+// Example:
+//
+//	chunks, err := tk.Chunk(document, 512, tokenizer.WithChunkOverlap(64))
+func (t *Tokenizer) Chunk(text string, maxTokens int, opts ...ChunkOption) ([]Chunk, error) {
+	o := DefaultChunkOptions(maxTokens)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.MaxTokens <= 0 {
+		return nil, errors.New("Tokenizer.Chunk() failed: max tokens must be positive")
+	}
+	if o.Overlap < 0 || o.Overlap >= o.MaxTokens {
+		return nil, errors.New("Tokenizer.Chunk() failed: overlap must be in [0, max tokens)")
+	}
+
+	en, err := t.EncodeSingleWithOptions(text,
+		WithAddSpecialTokens(false),
+		WithTruncationParams(nil),
+		WithPaddingParams(nil),
+		WithOffsetType(Byte),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c := chunker{text: text, offsets: en.Offsets}
+	n := len(c.offsets)
+
+	var (
+		chunks []Chunk
+		starts runeCounter
+		ends   runeCounter
+	)
+	for start := 0; start < n; {
+		stop := n
+		if start+o.MaxTokens < n {
+			stop = c.cut(start, start+o.MaxTokens, o)
+		}
+
+		byteStart := c.runeStart(c.offsets[start][0])
+		byteEnd := c.runeEnd(c.offsets[stop-1][1])
+		if trimmed := strings.TrimSpace(text[byteStart:byteEnd]); trimmed != "" {
+			byteStart += strings.Index(text[byteStart:byteEnd], trimmed)
+			byteEnd = byteStart + len(trimmed)
+		}
+		chunks = append(chunks, Chunk{
+			Text:   text[byteStart:byteEnd],
+			Start:  starts.count(text, byteStart),
+			End:    ends.count(text, byteEnd),
+			Tokens: stop - start,
+		})
+
+		if stop == n {
+			break
+		}
+
+		next := stop - o.Overlap
+		for next < stop && !c.canCut(next) {
+			next++
+		}
+		start = next
+	}
+
+	return chunks, nil
+}
+
+// chunker finds chunk boundaries in a text from its token offsets.
+type chunker struct {
+	text    string
+	offsets [][]int
+}
+
+// canCut reports whether a chunk can end before token i, i.e. token i doesn't
+// share a character with token i-1.
+func (c *chunker) canCut(i int) bool {
+	return i > 0 && i < len(c.offsets) && c.offsets[i-1][1] <= c.offsets[i][0]
+}
+
+// cut returns the end (exclusive token index) of the chunk starting at token
+// `start`, of at most `limit` tokens. It's after `start + overlap` so that
+// the next chunk makes progress.
+func (c *chunker) cut(start, limit int, o *ChunkOptions) int {
+	min := start + o.Overlap + 1
+	for _, boundary := range o.Boundaries {
+		for i := limit; i >= min; i-- {
+			if c.canCut(i) && c.isBoundary(i, boundary) {
+				return i
+			}
+		}
+	}
+
+	for i := limit; i >= min; i-- {
+		if c.canCut(i) {
+			return i
+		}
+	}
+
+	// A single character spans more than `limit` tokens
+	i := limit + 1
+	for i < len(c.offsets) && !c.canCut(i) {
+		i++
+	}
+	return i
+}
+
+// isBoundary reports whether there is a boundary of the given kind before token i.
+// The gap between the tokens includes the leading whitespaces of token i, as
+// Metaspace and byte-level tokens keep the whitespace before a word (`▁was`).
+func (c *chunker) isBoundary(i int, boundary ChunkBoundary) bool {
+	before := strings.TrimRightFunc(c.text[:c.offsets[i-1][1]], unicode.IsSpace)
+	token := c.text[c.offsets[i][0]:c.offsets[i][1]]
+	gapEnd := c.offsets[i][1] - len(strings.TrimLeftFunc(token, unicode.IsSpace))
+	gap := c.text[len(before):gapEnd]
+
+	switch boundary {
+	case ParagraphBoundary:
+		newLines := 0
+		for _, r := range gap {
+			if r == '\n' {
+				newLines++
+			}
+		}
+		return newLines >= 2
+
+	case SentenceBoundary:
+		for _, r := range gap {
+			if r == '\n' {
+				return true
+			}
+		}
+		last, _ := utf8.DecodeLastRuneInString(before)
+		switch last {
+		case '.', '!', '?', '…':
+			return hasSpace(gap)
+		case '。', '！', '？':
+			return true
+		}
+		return false
+
+	case WhitespaceBoundary:
+		return hasSpace(gap)
+
+	case HardCut:
+		return true
+	}
+
+	return false
+}
+
+// runeStart moves a byte offset back to the start of its character.
+func (c *chunker) runeStart(i int) int {
+	for i > 0 && i < len(c.text) && !utf8.RuneStart(c.text[i]) {
+		i--
+	}
+	return i
+}
+
+// runeEnd moves a byte offset forward to the end of its character.
+func (c *chunker) runeEnd(i int) int {
+	for i < len(c.text) && !utf8.RuneStart(c.text[i]) {
+		i++
+	}
+	return i
+}
+
+func hasSpace(s string) bool {
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// runeCounter converts increasing byte offsets to rune offsets.
+type runeCounter struct {
+	bytePos int
+	runePos int
+}
+
+func (rc *runeCounter) count(s string, bytePos int) int {
+	rc.runePos += utf8.RuneCountInString(s[rc.bytePos:bytePos])
+	rc.bytePos = bytePos
+	return rc.runePos
+}
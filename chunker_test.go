@@ -0,0 +1,132 @@
+package tokenizer_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/model/wordlevel"
+	"github.com/sugarme/tokenizer/pretokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+// byteModel is a Model with one token per byte, like byte-level BPE for
+// unknown words.
+type byteModel struct {
+	tokenizer.Model
+}
+
+func (byteModel) Tokenize(sequence string) ([]tokenizer.Token, error) {
+	tokens := make([]tokenizer.Token, len(sequence))
+	for i := range tokens {
+		tokens[i] = tokenizer.Token{Id: int(sequence[i]), Value: sequence[i : i+1], Offsets: []int{i, i + 1}}
+	}
+	return tokens, nil
+}
+
+func TestChunk(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	text := "The cat sat on the mat. It was happy.\n\nThe dog barked at the cat. Then it slept."
+
+	chunks, err := tk.Chunk(text, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.Text)
+		if c.Tokens > 12 {
+			t.Errorf("chunk %q has %d tokens", c.Text, c.Tokens)
+		}
+		if want := string([]rune(text)[c.Start:c.End]); c.Text != want {
+			t.Errorf("want %q for char range [%d, %d), got %q", want, c.Start, c.End, c.Text)
+		}
+	}
+	want := []string{
+		"The cat sat on the mat. It was happy.",
+		"The dog barked at the cat. Then it slept.",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// Sentence boundaries when the paragraph doesn't fit
+	chunks, err = tk.Chunk(text, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks[0].Text != "The cat sat on the mat." {
+		t.Errorf("want first sentence, got %q", chunks[0].Text)
+	}
+
+	// Overlapping chunks
+	chunks, err = tk.Chunk(text, 8, tokenizer.WithChunkOverlap(3), tokenizer.WithChunkBoundaries(tokenizer.HardCut))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "The cat sat on the mat. It"; chunks[0].Text != want {
+		t.Errorf("want %q, got %q", want, chunks[0].Text)
+	}
+	if want := "mat. It was"; !strings.HasPrefix(chunks[1].Text, want) {
+		t.Errorf("want chunk starting with %q, got %q", want, chunks[1].Text)
+	}
+}
+
+func TestChunk_MultiByte(t *testing.T) {
+	tk := tokenizer.NewTokenizer(byteModel{})
+	text := "aé日本語b"
+
+	chunks, err := tk.Chunk(text, 4, tokenizer.WithChunkOverlap(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range chunks {
+		if !utf8.ValidString(c.Text) {
+			t.Errorf("chunk %q splits a character", c.Text)
+		}
+		if c.Tokens > 4 {
+			t.Errorf("chunk %q has %d tokens", c.Text, c.Tokens)
+		}
+		if want := string([]rune(text)[c.Start:c.End]); c.Text != want {
+			t.Errorf("want %q for char range [%d, %d), got %q", want, c.Start, c.End, c.Text)
+		}
+	}
+	if last := chunks[len(chunks)-1]; !strings.HasSuffix(last.Text, "b") {
+		t.Errorf("want last chunk ending with %q, got %q", "b", last.Text)
+	}
+}
+
+func TestChunk_Metaspace(t *testing.T) {
+	// Metaspace tokens keep the whitespace before them, e.g. "▁was"
+	vocab := map[string]int{"<unk>": 0}
+	for _, w := range strings.Fields("The cat sat. It was happy. Then slept.") {
+		vocab["▁"+w] = len(vocab)
+	}
+	model, err := wordlevel.New(vocab, "<unk>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk := tokenizer.NewTokenizer(model)
+	tk.WithPreTokenizer(pretokenizer.NewMetaspace("▁", true))
+
+	text := "The cat sat. It was happy. Then slept."
+	chunks, err := tk.Chunk(text, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.Text)
+		if want := string([]rune(text)[c.Start:c.End]); c.Text != want {
+			t.Errorf("want %q for char range [%d, %d), got %q", want, c.Start, c.End, c.Text)
+		}
+	}
+	if want := []string{"The cat sat.", "It was happy.", "Then slept."}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,130 @@
+package tokenizer_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+	"github.com/sugarme/tokenizer/processor"
+)
+
+func TestTokenizer_ConcurrentUpdates(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				en, err := tk.EncodeSingle("hello <tok1> world", true)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				tk.Decode(en.Ids, true)
+				if _, err := tk.EncodeBatchContext(context.Background(), batchInputs("hello", "world")); err != nil {
+					t.Error(err)
+					return
+				}
+				tk.GetVocab(true)
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		tk.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken(fmt.Sprintf("<tok%d>", i), false)})
+		tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 10 + i})
+		tk.WithPadding(nil)
+	}
+	wg.Wait()
+
+	en, err := tk.EncodeSingle("hello <tok1> world", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"hello", "<tok1>", "world"}; fmt.Sprint(want) != fmt.Sprint(en.Tokens) {
+		t.Errorf("want %v, got %v", want, en.Tokens)
+	}
+}
+
+func TestTokenizer_Clone(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	vocabSize := tk.GetVocabSize(true)
+
+	clone := tk.Clone()
+	clone.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("<new>", false)})
+	clone.WithTruncation(&tokenizer.TruncationParams{MaxLength: 8})
+
+	if tk.GetVocabSize(true) != vocabSize || tk.GetTruncation() != nil {
+		t.Errorf("clone modified the original tokenizer")
+	}
+	if clone.GetVocabSize(true) != vocabSize+1 {
+		t.Errorf("want %d tokens in clone, got %d", vocabSize+1, clone.GetVocabSize(true))
+	}
+	if _, ok := tk.TokenToId("<new>"); ok {
+		t.Errorf("token added to the clone found in the original tokenizer")
+	}
+}
+
+func TestTokenizer_CloneComponents(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	builder := processor.DefaultTemplateProcessing().Builder()
+	builder.NewSingle("[CLS] $A [SEP]")
+	builder.NewPair("[CLS] $A [SEP] $B:1 [SEP]:1")
+	builder.NewSpecialTokens([]tokenizer.Token{{Id: 101, Value: "[CLS]"}, {Id: 102, Value: "[SEP]"}})
+	tk.WithPostProcessor(builder.Build())
+
+	clone := tk.Clone()
+	if clone.GetPostProcessor() == tk.GetPostProcessor() {
+		t.Fatalf("clone shares the post-processor")
+	}
+
+	// Modifying the template of the clone in place doesn't affect the original
+	template := clone.GetPostProcessor().(*processor.TemplateProcessing)
+	template.Single[0].(*processor.SpecialTokenPiece).Id = "[SEP]"
+
+	en, err := tk.EncodeSingle("hello", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"[CLS]", "hello", "[SEP]"}; fmt.Sprint(want) != fmt.Sprint(en.Tokens) {
+		t.Errorf("want %v, got %v", want, en.Tokens)
+	}
+	if en, err = clone.EncodeSingle("hello", true); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"[SEP]", "hello", "[SEP]"}; fmt.Sprint(want) != fmt.Sprint(en.Tokens) {
+		t.Errorf("want %v, got %v", want, en.Tokens)
+	}
+}
+
+func TestTokenizer_Update(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	err := tk.Update(func(tk *tokenizer.Tokenizer) error {
+		tk.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("<new>", false)})
+		return fmt.Errorf("invalid")
+	})
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if _, ok := tk.TokenToId("<new>"); ok {
+		t.Errorf("failed update has been applied")
+	}
+
+	err = tk.Update(func(tk *tokenizer.Tokenizer) error {
+		tk.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("<new>", false)})
+		tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 8})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tk.TokenToId("<new>"); !ok || tk.GetTruncation() == nil {
+		t.Errorf("update has not been applied")
+	}
+}
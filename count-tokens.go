@@ -0,0 +1,93 @@
+package tokenizer
+
+import (
+	"context"
+	"errors"
+)
+
+// TokenCounter is an optional interface of a `Model` that can count the tokens
+// of a sequence without building them, i.e. without allocating their values and
+// offsets. `Tokenizer.CountTokens` falls back to `Model.Tokenize` for models
+// that don't implement it.
+type TokenCounter interface {
+	// CountTokens returns the number of tokens `Tokenize` returns for the sequence.
+	CountTokens(sequence string) (int, error)
+}
+
+// CountTokens returns the number of tokens of the given text, running the
+// normalizer, the pre-tokenizer and the model like `EncodeSingle` but without
+// building an `Encoding`. If `addSpecialTokens` is true, the tokens added by
+// the post-processor are counted as well.
+//
+// Of the given options, only the special token policy applies
+// (`WithSplitSpecialTokens`, `WithAllowedSpecial` and `WithDisallowedSpecial`),
+// so that untrusted text is counted as it is encoded. Truncation and padding
+// are not applied: the count is the full length of the text.
+func (t *Tokenizer) CountTokens(text string, addSpecialTokens bool, opts ...EncodeOption) (int, error) {
+	c := t.load()
+	if c.model == nil {
+		return 0, errors.New("Tokenizer.CountTokens() failed: there's no 'Tokenizer Model' setup")
+	}
+	o := c.newEncodeOptions(opts...)
+
+	pretokenized, err := c.addedVocabulary.extractAndNormalize(text, c.normalizer, o.specialFilter())
+	if err != nil {
+		return 0, err
+	}
+	if c.preTokenizer != nil {
+		pretokenized, err = c.doPreTokenize(pretokenized)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	for _, split := range pretokenized.splits {
+		// Added tokens have already been tokenized
+		if split.tokens != nil {
+			count += len(split.tokens)
+			continue
+		}
+
+		n, err := c.countModelTokens(split.normalized.GetNormalized())
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+
+	if addSpecialTokens && c.postProcessor != nil {
+		count += c.postProcessor.AddedTokens(false)
+	}
+
+	return count, nil
+}
+
+func (c *tokenizerConfig) countModelTokens(sequence string) (int, error) {
+	if counter, ok := c.model.(TokenCounter); ok {
+		return counter.CountTokens(sequence)
+	}
+
+	tokens, err := c.model.Tokenize(sequence)
+	return len(tokens), err
+}
+
+// CountTokensBatch counts the tokens of all texts like `CountTokens`, using the
+// `Concurrency` and `AddSpecialTokens` settings of the given options and the
+// special token policy of their `EncodeOptions`. Texts that failed are counted
+// as 0 and reported in a *BatchError.
+func (t *Tokenizer) CountTokensBatch(ctx context.Context, texts []string, opts ...BatchOption) ([]int, error) {
+	o := newBatchOptions(opts...)
+	counts := make([]int, len(texts))
+
+	err := runBatch(ctx, len(texts), o.Concurrency, func(i int) error {
+		n, err := t.CountTokens(texts[i], o.AddSpecialTokens, o.EncodeOptions...)
+		if err != nil {
+			return err
+		}
+		counts[i] = n
+		return nil
+	})
+
+	return counts, err
+}
@@ -0,0 +1,124 @@
+package tokenizer_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+var countTexts = []string{
+	"",
+	"hello",
+	"Yesterday I saw a [MASK] far away",
+	"unaffable tokenization of antidisestablishmentarianism",
+	"日本語のテキスト 👍🏽",
+}
+
+func TestCountTokens(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	for _, addSpecialTokens := range []bool{false, true} {
+		for _, text := range countTexts {
+			en, err := tk.EncodeSingle(text, addSpecialTokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tk.CountTokens(text, addSpecialTokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != en.Len() {
+				t.Errorf("%q (special tokens: %v): want %d tokens, got %d", text, addSpecialTokens, en.Len(), got)
+			}
+		}
+	}
+}
+
+func TestCountTokens_WordLevel(t *testing.T) {
+	tk := newWordLevelTokenizer(t, []string{"[UNK]", "hello", "world"}, nil)
+	tk.WithPreTokenizer(pretokenizer.NewWhitespaceSplit())
+
+	got, err := tk.CountTokens("hello big world", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("want 3 tokens, got %d", got)
+	}
+}
+
+func TestCountTokens_SpecialTokensPolicy(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	text := "hi [SEP] there"
+
+	for _, opts := range [][]tokenizer.EncodeOption{
+		nil,
+		{tokenizer.WithSplitSpecialTokens(true)},
+		{tokenizer.WithAllowedSpecial("[SEP]"), tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens)},
+	} {
+		en, err := tk.EncodeSingleWithOptions(text, append(opts, tokenizer.WithAddSpecialTokens(false))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := tk.CountTokens(text, false, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != en.Len() {
+			t.Errorf("%d options: want %d tokens, got %d", len(opts), en.Len(), got)
+		}
+	}
+
+	if _, err := tk.CountTokens(text, false, tokenizer.WithDisallowedSpecial("[SEP]")); !errors.Is(err, tokenizer.ErrDisallowedSpecial) {
+		t.Errorf("want ErrDisallowedSpecial, got %v", err)
+	}
+}
+
+func TestCountTokensBatch(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	got, err := tk.CountTokensBatch(context.Background(), countTexts, tokenizer.WithBatchAddSpecialTokens(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want []int
+	for _, text := range countTexts {
+		n, err := tk.CountTokens(text, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, n)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+const benchmarkText = "The quick brown fox jumps over the lazy dog, unaffably tokenizing antidisestablishmentarianism."
+
+func BenchmarkEncodeSingle(b *testing.B) {
+	tk := pretrained.BertBaseUncased()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tk.EncodeSingle(benchmarkText, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountTokens(b *testing.B) {
+	tk := pretrained.BertBaseUncased()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tk.CountTokens(benchmarkText, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
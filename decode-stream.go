@@ -0,0 +1,151 @@
+package tokenizer
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidPrefix is returned by `DecodeStream.Step` when decoding the
+// current window does not start with the text decoded previously.
+var ErrInvalidPrefix = errors.New("decode stream: decoded text does not start with the previous prefix")
+
+// DecodeStream decodes ids one at a time, i.e. while they are generated.
+//
+// Decoding each id on its own is wrong for many decoders: byte-level and
+// byte-fallback tokens can split a UTF-8 character, and Metaspace or WordPiece
+// decoders need the previous token to know whether to add a space. DecodeStream
+// keeps a small window of the latest ids, decodes it and emits only the text
+// that has been completed since the previous step. Incomplete UTF-8 sequences
+// are held back until the ids completing them arrive.
+//
+// Example:
+//
+//	stream := tokenizer.NewDecodeStream(tk, tokenizer.WithSkipSpecialTokens(true))
+//	for _, id := range generatedIds {
+//		text, err := stream.Step(id)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Print(text)
+//	}
+type DecodeStream struct {
+	tokenizer *Tokenizer
+	opts      *DecodeOptions
+
+	// ids of the current window
+	ids []int
+	// text decoded from `ids[:prefixIndex]`
+	prefix string
+	// number of ids of the window already emitted
+	prefixIndex int
+}
+
+// NewDecodeStream creates a DecodeStream for the given tokenizer.
+func NewDecodeStream(tk *Tokenizer, opts ...DecodeOption) *DecodeStream {
+	return &DecodeStream{
+		tokenizer: tk,
+		opts:      newDecodeOptions(opts...),
+	}
+}
+
+// DecodeStream creates a DecodeStream for the tokenizer.
+func (t *Tokenizer) DecodeStream(opts ...DecodeOption) *DecodeStream {
+	return NewDecodeStream(t, opts...)
+}
+
+// Step adds the given id to the stream and returns the newly completed text.
+// It returns an empty string if the id did not complete any text (yet).
+func (s *DecodeStream) Step(id int) (string, error) {
+	s.ids = append(s.ids, id)
+	text, err := s.decode(s.ids)
+	if err != nil {
+		return "", err
+	}
+
+	if len(text) <= len(s.prefix) || incompleteUTF8(text) {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(text, s.prefix) {
+		return "", ErrInvalidPrefix
+	}
+
+	newText := text[len(s.prefix):]
+
+	// Keep the last emitted ids as context for the next step.
+	newPrefixIndex := len(s.ids) - s.prefixIndex
+	s.ids = append([]int(nil), s.ids[s.prefixIndex:]...)
+	s.prefix, err = s.decode(s.ids)
+	if err != nil {
+		return "", err
+	}
+	s.prefixIndex = newPrefixIndex
+
+	return newText, nil
+}
+
+// Flush returns the text held back by the stream, even if it ends with an
+// incomplete UTF-8 sequence, and resets the stream.
+func (s *DecodeStream) Flush() (string, error) {
+	text, err := s.decode(s.ids)
+	if err != nil {
+		return "", err
+	}
+	prefix := s.prefix
+	s.Reset()
+
+	if len(text) <= len(prefix) {
+		return "", nil
+	}
+
+	return strings.TrimPrefix(text, prefix), nil
+}
+
+// Reset clears the stream so that it can be used for a new sequence.
+func (s *DecodeStream) Reset() {
+	s.ids = nil
+	s.prefix = ""
+	s.prefixIndex = 0
+}
+
+func (s *DecodeStream) decode(ids []int) (string, error) {
+	c := s.tokenizer.load()
+	tokens, err := c.idsToTokens(ids, s.opts)
+	if err != nil {
+		return "", err
+	}
+
+	return c.decodeTokens(tokens), nil
+}
+
+// incompleteUTF8 returns whether the text ends with a replacement character or
+// with the beginning of a multi-byte UTF-8 sequence.
+func incompleteUTF8(text string) bool {
+	if strings.HasSuffix(text, string(utf8.RuneError)) {
+		return true
+	}
+
+	// Look for the start byte of the last character
+	for i := 1; i <= utf8.UTFMax && i <= len(text); i++ {
+		b := text[len(text)-i]
+		if utf8.RuneStart(b) {
+			var size int
+			switch {
+			case b < 0x80:
+				size = 1
+			case b&0xE0 == 0xC0:
+				size = 2
+			case b&0xF0 == 0xE0:
+				size = 3
+			case b&0xF8 == 0xF0:
+				size = 4
+			default: // invalid start byte, can't be completed
+				return false
+			}
+			return size > i
+		}
+	}
+
+	return false
+}
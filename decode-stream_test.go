@@ -0,0 +1,98 @@
+package tokenizer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/decoder"
+	"github.com/sugarme/tokenizer/model/wordlevel"
+	"github.com/sugarme/tokenizer/pretokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func newWordLevelTokenizer(t *testing.T, tokens []string, dec tokenizer.Decoder) *tokenizer.Tokenizer {
+	vocab := make(map[string]int)
+	for i, tok := range tokens {
+		vocab[tok] = i
+	}
+	model, err := wordlevel.New(vocab, tokens[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk := tokenizer.NewTokenizer(model)
+	tk.WithDecoder(dec)
+
+	return tk
+}
+
+func streamAll(t *testing.T, tk *tokenizer.Tokenizer, ids []int) []string {
+	stream := tk.DecodeStream()
+	var out []string
+	for _, id := range ids {
+		text, err := stream.Step(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, text)
+	}
+
+	return out
+}
+
+func TestDecodeStream_ByteFallback(t *testing.T) {
+	metaspace := pretokenizer.NewMetaspace("▁", true)
+	dec := decoder.NewSequence([]tokenizer.Decoder{decoder.NewByteFallback(), metaspace})
+	tk := newWordLevelTokenizer(t, []string{"<unk>", "▁Hello", "▁world", "<0xE2>", "<0x82>", "<0xAC>", "▁"}, dec)
+
+	ids := []int{1, 2, 6, 3, 4, 5}
+	got := streamAll(t, tk, ids)
+	want := []string{"Hello", " world", " ", "", "", "€"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if strings.Join(got, "") != tk.Decode(ids, false) {
+		t.Errorf("stream %q differs from decode %q", strings.Join(got, ""), tk.Decode(ids, false))
+	}
+}
+
+func TestDecodeStream_ByteLevel(t *testing.T) {
+	bc := pretokenizer.BytesChar
+	euro := []byte("€")
+	tokens := []string{"<unk>", "Hi", bc[' '] + bc[euro[0]], bc[euro[1]] + bc[euro[2]], bc['!']}
+	tk := newWordLevelTokenizer(t, tokens, pretokenizer.NewByteLevel())
+
+	got := streamAll(t, tk, []int{1, 2, 3, 4})
+	want := []string{"Hi", "", " €", "!"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	stream := tk.DecodeStream()
+	stream.Step(2)
+	if text, err := stream.Flush(); err != nil || text != string([]byte{' ', euro[0]}) {
+		t.Errorf("unexpected flush %q, %v", text, err)
+	}
+}
+
+func TestDecodeStream_WordPiece(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	en, err := tk.EncodeSingle("yesterday i saw an unaffable giraffe", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := tk.DecodeStream(tokenizer.WithSkipSpecialTokens(true))
+	var text string
+	for _, id := range en.Ids {
+		s, err := stream.Step(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text += s
+	}
+
+	if want := tk.Decode(en.Ids, true); text != want {
+		t.Errorf("want %q, got %q", want, text)
+	}
+}
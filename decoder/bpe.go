@@ -27,9 +27,14 @@ func NewBpeDecoder(suffix string) *BpeDecoder {
 	return d
 }
 
+// GetSuffix returns the suffix identifying end-of-words.
+func (bd *BpeDecoder) GetSuffix() string {
+	return bd.suffix
+}
+
 // DefaultBpeDecoder create a new BpeDecoder with default suffix (`</w>`)
 func DefaultBpeDecoder() *BpeDecoder {
-	return &BpeDecoder{suffix: "</w>"}
+	return NewBpeDecoder("</w>")
 }
 
 /*
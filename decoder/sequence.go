@@ -25,6 +25,21 @@ func NewSequence(decoders []tokenizer.Decoder) *Sequence {
 	return seq
 }
 
+// Decoders returns the decoders of the sequence.
+func (d *Sequence) Decoders() []tokenizer.Decoder {
+	return d.decoders
+}
+
+// Clone implements tokenizer.Cloner.
+func (d *Sequence) Clone() tokenizer.Decoder {
+	decoders := make([]tokenizer.Decoder, len(d.decoders))
+	for i, dec := range d.decoders {
+		decoders[i] = tokenizer.CloneComponent(dec)
+	}
+
+	return NewSequence(decoders)
+}
+
 // Decode implements `tokenizer.Decoder` interface.
 func (d *Sequence) DecodeChain(tokens []string) []string {
 	var input []string
@@ -31,12 +31,19 @@ func NewWordPieceDecoder(prefix string, cleanup bool) *WordPieceDecoder {
 	return d
 }
 
+// GetPrefix returns the prefix of continuing subwords.
+func (wd *WordPieceDecoder) GetPrefix() string {
+	return wd.prefix
+}
+
+// GetCleanup returns whether tokenization artifacts are cleaned up.
+func (wd *WordPieceDecoder) GetCleanup() bool {
+	return wd.cleanup
+}
+
 // DefaultBpeDecoder create a new BpeDecoder with default suffix (`</w>`)
 func DefaultWordpieceDecoder() *WordPieceDecoder {
-	return &WordPieceDecoder{
-		prefix:  "##",
-		cleanup: true,
-	}
+	return NewWordPieceDecoder("##", true)
 }
 
 /*
@@ -0,0 +1,149 @@
+package tokenizer
+
+import "github.com/sugarme/tokenizer/normalizer"
+
+// EncodeAppend encodes `text + appended`, where `prev` is the encoding of
+// `text` with the same options, running the model only from the last stable
+// pre-token boundary of `text`. The result is the same as encoding the full
+// text from scratch.
+//
+// It also returns the index of the first token whose id differs from
+// `prev.Ids`, so that the cache of a model (e.g. the KV cache of a language
+// model) can be reused up to that token. It's `len(prev.Ids)` if the new ids
+// only extend the previous ones.
+//
+// The words of `text` are reused when they are pre-tokenized the same way in
+// the full text, except the last one that may be continued by `appended`.
+// Only the model tokenization of these words is saved: the full text is still
+// normalized and pre-tokenized, as both may depend on the preceding text
+// (e.g. Metaspace prepend schemes), so the cost of each call grows with the
+// length of the full text.
+// `prev` must have been encoded from a raw string, with its offsets and
+// words. Otherwise, or if it has been truncated, the full text is encoded.
+//
+// Example:
+//
+//	en, _ := tk.EncodeSingle(conversation)
+//	en, divergeAt, err := tk.EncodeAppend(en, conversation, message)
+//	conversation += message
+func (t *Tokenizer) EncodeAppend(prev *Encoding, text, appended string, opts ...EncodeOption) (*Encoding, int, error) {
+	c := t.load()
+	o := c.newEncodeOptions(opts...)
+	full := text + appended
+
+	encoding, err := c.encodeAppended(prev, full, o.OffsetType, o.specialFilter())
+	if err != nil {
+		return nil, 0, err
+	}
+	if encoding == nil {
+		if encoding, err = c.encodeSingleSequence(NewInputSequence(full), 0, o.OffsetType, o.specialFilter()); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	finalEncoding, err := c.postProcess(encoding, nil, o.AddSpecialTokens, o.Truncation, o.Padding)
+	if err != nil {
+		return nil, 0, err
+	}
+	finalEncoding.setOffsetType(o.OffsetType)
+	o.strip(finalEncoding)
+
+	var prevIds []int
+	if prev != nil {
+		prevIds = prev.Ids
+	}
+
+	return finalEncoding, divergence(prevIds, finalEncoding.Ids), nil
+}
+
+// encodeAppended encodes the full text without post-processing, reusing the
+// tokens of the stable words of `prev`. It returns nil if `prev` can't be reused.
+func (c *tokenizerConfig) encodeAppended(prev *Encoding, full string, offsetType OffsetType, filter specialFilter) (*Encoding, error) {
+	if prev == nil || len(prev.Overflowing) > 0 || prev.OffsetType != offsetType ||
+		len(prev.Words) != len(prev.Ids) || len(prev.Offsets) != len(prev.Ids) {
+		return nil, nil
+	}
+
+	// Tokens of `text`, grouped by word. Tokens added by the post-processor
+	// and padding have no word.
+	var words [][]int
+	for i, w := range prev.Words {
+		switch {
+		case w < 0:
+			continue
+		case w == len(words):
+			words = append(words, []int{i})
+		case w == len(words)-1:
+			words[w] = append(words[w], i)
+		default:
+			return nil, nil
+		}
+	}
+	if len(words) < 2 {
+		return nil, nil
+	}
+
+	pretokenized, err := c.addedVocabulary.extractAndNormalize(full, c.normalizer, filter)
+	if err != nil {
+		return nil, err
+	}
+	if c.preTokenizer != nil {
+		pretokenized, err = c.doPreTokenize(pretokenized)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Stable words have the same offsets in the full text. The last word may
+	// be continued by the appended text.
+	splits := pretokenized.GetSplits(normalizer.OriginalTarget, offsetType)
+	stable := 0
+	for stable < len(words)-1 && stable < len(splits) {
+		tokens := words[stable]
+		start := prev.Offsets[tokens[0]][0]
+		end := prev.Offsets[tokens[len(tokens)-1]][1]
+		offsets := splits[stable].Offsets
+		if len(offsets) != 2 || offsets[0] != start || offsets[1] != end {
+			break
+		}
+		stable++
+	}
+	if stable == 0 {
+		return nil, nil
+	}
+
+	head := DefaultEncoding()
+	for _, tokens := range words[:stable] {
+		for _, i := range tokens {
+			head.Ids = append(head.Ids, prev.Ids[i])
+			head.TypeIds = append(head.TypeIds, 0)
+			head.Tokens = append(head.Tokens, prev.Tokens[i])
+			head.Offsets = append(head.Offsets, prev.Offsets[i])
+			head.Words = append(head.Words, prev.Words[i])
+			head.SpecialTokenMask = append(head.SpecialTokenMask, 0)
+			head.AttentionMask = append(head.AttentionMask, 1)
+		}
+	}
+	head.OffsetType = offsetType
+
+	pretokenized.splits = pretokenized.splits[stable:]
+	tail, err := c.doTokenize(pretokenized, 0, -1, offsetType)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tail.Words {
+		tail.Words[i] += stable
+	}
+
+	return DefaultEncoding().Merge([]Encoding{*head, *tail}, false), nil
+}
+
+// divergence returns the index of the first different id.
+func divergence(prev, ids []int) int {
+	i := 0
+	for i < len(prev) && i < len(ids) && prev[i] == ids[i] {
+		i++
+	}
+
+	return i
+}
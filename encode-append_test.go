@@ -0,0 +1,69 @@
+package tokenizer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func TestEncodeAppend(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	tests := []struct {
+		text, appended string
+		opts           []tokenizer.EncodeOption
+	}{
+		{"Hello, how are you?", " I am fine.", nil},
+		{"Hello, how are you? I am fi", "ne, thanks.", nil},
+		{"Hello [MASK] world", " again", []tokenizer.EncodeOption{tokenizer.WithAddSpecialTokens(true)}},
+		{"Les élèves étaient", " à l'école", []tokenizer.EncodeOption{tokenizer.WithOffsetType(tokenizer.Char)}},
+		{"hello", " world", nil},
+		{"", "hello world", nil},
+	}
+
+	for _, tt := range tests {
+		prev, err := tk.EncodeSingleWithOptions(tt.text, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := tk.EncodeSingleWithOptions(tt.text+tt.appended, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, divergeAt, err := tk.EncodeAppend(prev, tt.text, tt.appended, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%q + %q:\nwant %+v\ngot  %+v", tt.text, tt.appended, want, got)
+		}
+
+		wantDivergeAt := 0
+		for wantDivergeAt < len(prev.Ids) && wantDivergeAt < len(want.Ids) && prev.Ids[wantDivergeAt] == want.Ids[wantDivergeAt] {
+			wantDivergeAt++
+		}
+		if divergeAt != wantDivergeAt {
+			t.Errorf("%q + %q: want divergence at %d, got %d", tt.text, tt.appended, wantDivergeAt, divergeAt)
+		}
+	}
+}
+
+func TestEncodeAppend_Divergence(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	prev, err := tk.EncodeSingle("hello wor", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// [CLS] hello wo ##r [SEP] -> [CLS] hello world [SEP]
+	_, divergeAt, err := tk.EncodeAppend(prev, "hello wor", "ld", tokenizer.WithAddSpecialTokens(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergeAt != 2 {
+		t.Errorf("want divergence at 2, got %d (%v)", divergeAt, prev.Tokens)
+	}
+}
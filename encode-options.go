@@ -0,0 +1,256 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EncodeOptions holds the options of a single encoding call.
+//
+// They are resolved from the tokenizer configuration (truncation and padding
+// params) and then overridden by the given `EncodeOption`s, so callers sharing
+// one tokenizer can use different settings without modifying it.
+type EncodeOptions struct {
+	// Whether to add special tokens
+	AddSpecialTokens bool
+	// Truncation params. Nil means no truncation.
+	Truncation *TruncationParams
+	// Padding params. Nil means no padding.
+	Padding *PaddingParams
+	// Type of offsets: Byte, Char, UTF16 or Grapheme
+	OffsetType OffsetType
+	// Whether to return `Offsets`. Without them, offsets are not converted to
+	// `OffsetType`.
+	ReturnOffsets bool
+	// Whether to return `Words`. Words and masks cost little to build, so they
+	// are built anyway and only left out of the result.
+	ReturnWords bool
+	// Whether to return `AttentionMask` and `SpecialTokenMask`
+	ReturnMasks bool
+	// Whether to encode the content of special tokens found in the text as
+	// ordinary text, except for `AllowedSpecial` tokens. E.g. to prevent user
+	// text from injecting control tokens.
+	SplitSpecialTokens bool
+	// Special tokens still extracted from the text with `SplitSpecialTokens`,
+	// and never disallowed.
+	AllowedSpecial []string
+	// Special tokens making encoding fail with `ErrDisallowedSpecial` if found
+	// in the text. `AllSpecialTokens` disallows all of them.
+	DisallowedSpecial []string
+}
+
+// AllSpecialTokens stands for all the special tokens in `DisallowedSpecial`.
+const AllSpecialTokens = "all"
+
+// ErrDisallowedSpecial is returned when the text to encode contains a special
+// token listed in `EncodeOptions.DisallowedSpecial`.
+var ErrDisallowedSpecial = errors.New("disallowed special token")
+
+type EncodeOption func(o *EncodeOptions)
+
+// WithAddSpecialTokens specifies whether to add special tokens.
+func WithAddSpecialTokens(v bool) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.AddSpecialTokens = v
+	}
+}
+
+// WithTruncationParams replaces the truncation params. Nil disables truncation.
+func WithTruncationParams(v *TruncationParams) EncodeOption {
+	return func(o *EncodeOptions) {
+		if v == nil {
+			o.Truncation = nil
+			return
+		}
+		params := *v
+		o.Truncation = &params
+	}
+}
+
+// WithMaxLength sets the truncation max length, enabling truncation
+// with `LongestFirst` strategy if it was not set.
+func WithMaxLength(v int) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.truncation().MaxLength = v
+	}
+}
+
+// WithTruncationStrategy sets the truncation strategy.
+func WithTruncationStrategy(v TruncationStrategy) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.truncation().Strategy = v
+	}
+}
+
+// WithStride sets the truncation stride.
+func WithStride(v int) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.truncation().Stride = v
+	}
+}
+
+// WithTruncationDirection sets which side of the sequence is truncated.
+func WithTruncationDirection(v TruncationDirection) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.truncation().Direction = v
+	}
+}
+
+// WithPaddingParams replaces the padding params. Nil disables padding.
+func WithPaddingParams(v *PaddingParams) EncodeOption {
+	return func(o *EncodeOptions) {
+		if v == nil {
+			o.Padding = nil
+			return
+		}
+		params := *v
+		o.Padding = &params
+	}
+}
+
+// WithOffsetType sets the type of offsets: Byte, Char, UTF16 or Grapheme.
+func WithOffsetType(v OffsetType) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.OffsetType = v
+	}
+}
+
+// WithReturnOffsets specifies whether to return offsets.
+func WithReturnOffsets(v bool) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.ReturnOffsets = v
+	}
+}
+
+// WithReturnWords specifies whether to return word indexes.
+func WithReturnWords(v bool) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.ReturnWords = v
+	}
+}
+
+// WithReturnMasks specifies whether to return attention and special token masks.
+func WithReturnMasks(v bool) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.ReturnMasks = v
+	}
+}
+
+// WithSplitSpecialTokens specifies whether to encode the content of special
+// tokens found in the text as ordinary text.
+func WithSplitSpecialTokens(v bool) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.SplitSpecialTokens = v
+	}
+}
+
+// WithAllowedSpecial extracts only the given special tokens from the text: the
+// content of the other ones is encoded as ordinary text, unless disallowed. Like
+// `allowed_special` of tiktoken.
+func WithAllowedSpecial(tokens ...string) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.SplitSpecialTokens = true
+		o.AllowedSpecial = append([]string{}, tokens...)
+	}
+}
+
+// WithDisallowedSpecial makes encoding fail with `ErrDisallowedSpecial` if the
+// text contains one of the given special tokens, or any special token that is
+// not allowed with `AllSpecialTokens`. Like `disallowed_special` of tiktoken.
+func WithDisallowedSpecial(tokens ...string) EncodeOption {
+	return func(o *EncodeOptions) {
+		o.DisallowedSpecial = append([]string{}, tokens...)
+	}
+}
+
+// DefaultEncodeOptions returns options without truncation nor padding.
+func DefaultEncodeOptions() *EncodeOptions {
+	return &EncodeOptions{
+		AddSpecialTokens: false,
+		Truncation:       nil,
+		Padding:          nil,
+		OffsetType:       Byte,
+		ReturnOffsets:    true,
+		ReturnWords:      true,
+		ReturnMasks:      true,
+	}
+}
+
+// truncation returns truncation params, creating default ones if needed.
+func (o *EncodeOptions) truncation() *TruncationParams {
+	if o.Truncation == nil {
+		o.Truncation = &TruncationParams{Strategy: LongestFirst}
+	}
+
+	return o.Truncation
+}
+
+// offsetType returns the type of offsets to compute: byte offsets, which need
+// no conversion, if offsets are not returned.
+func (o *EncodeOptions) offsetType() OffsetType {
+	if !o.ReturnOffsets {
+		return Byte
+	}
+
+	return o.OffsetType
+}
+
+// strip removes the outputs that have not been asked for.
+func (o *EncodeOptions) strip(e *Encoding) {
+	if !o.ReturnOffsets {
+		e.Offsets = nil
+	}
+	if !o.ReturnWords {
+		e.Words = nil
+	}
+	if !o.ReturnMasks {
+		e.AttentionMask = nil
+		e.SpecialTokenMask = nil
+	}
+	for i := range e.Overflowing {
+		o.strip(&e.Overflowing[i])
+	}
+}
+
+// specialFilter returns the filter of the special tokens extracted from the
+// text, nil to extract all of them.
+func (o *EncodeOptions) specialFilter() specialFilter {
+	if !o.SplitSpecialTokens && len(o.DisallowedSpecial) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(o.AllowedSpecial))
+	for _, tok := range o.AllowedSpecial {
+		allowed[tok] = true
+	}
+	disallowed := make(map[string]bool, len(o.DisallowedSpecial))
+	for _, tok := range o.DisallowedSpecial {
+		disallowed[tok] = true
+	}
+	split := o.SplitSpecialTokens
+
+	return func(token string) (bool, error) {
+		switch {
+		case allowed[token]:
+			return true, nil
+		case disallowed[token] || disallowed[AllSpecialTokens]:
+			return false, fmt.Errorf("%w: %q", ErrDisallowedSpecial, token)
+		}
+
+		return !split, nil
+	}
+}
+
+// newEncodeOptions resolves encode options from the tokenizer configuration
+// and the given options.
+func (c *tokenizerConfig) newEncodeOptions(opts ...EncodeOption) *EncodeOptions {
+	o := DefaultEncodeOptions()
+	WithTruncationParams(c.trunc)(o)
+	WithPaddingParams(c.padding)(o)
+	for _, opt := range opts {
+		opt(o)
+	}
+	c.withPadToken(o.Padding)
+
+	return o
+}
@@ -0,0 +1,261 @@
+package tokenizer_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func TestEncodeWithOptions(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 6, Strategy: tokenizer.LongestFirst})
+
+	sentence := "yesterday i saw a cat far away from home"
+
+	var wg sync.WaitGroup
+	for _, maxLen := range []int{4, 8, 10} {
+		wg.Add(1)
+		go func(maxLen int) {
+			defer wg.Done()
+			en, err := tk.EncodeSingleWithOptions(sentence, tokenizer.WithAddSpecialTokens(true), tokenizer.WithMaxLength(maxLen))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if en.Len() != maxLen {
+				t.Errorf("max length %d: got %d tokens", maxLen, en.Len())
+			}
+		}(maxLen)
+	}
+	wg.Wait()
+
+	// The tokenizer configuration is left untouched
+	if got := tk.GetTruncation().MaxLength; got != 6 {
+		t.Errorf("tokenizer truncation modified: max length %d", got)
+	}
+	en, err := tk.EncodeSingle(sentence, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if en.Len() != 6 {
+		t.Errorf("want 6 tokens, got %d", en.Len())
+	}
+
+	// Disable truncation, pad and skip some outputs
+	padding := &tokenizer.PaddingParams{
+		Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithFixed(16)),
+		Direction: tokenizer.Right,
+		PadToken:  "[PAD]",
+	}
+	en, err = tk.EncodePairWithOptions("hello", "far away",
+		tokenizer.WithAddSpecialTokens(false),
+		tokenizer.WithTruncationParams(nil),
+		tokenizer.WithPaddingParams(padding),
+		tokenizer.WithReturnOffsets(false),
+		tokenizer.WithReturnWords(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if en.Len() != 16 {
+		t.Errorf("want 16 tokens, got %d", en.Len())
+	}
+	if en.Offsets != nil || en.Words != nil || en.AttentionMask == nil {
+		t.Errorf("unexpected outputs: offsets %v, words %v, attention mask %v", en.Offsets, en.Words, en.AttentionMask)
+	}
+	if tk.GetPadding() != nil {
+		t.Errorf("tokenizer padding modified")
+	}
+}
+
+func TestEncodeWithTruncationDirection(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	en, err := tk.EncodeSingleWithOptions("yesterday i saw a cat far away from home",
+		tokenizer.WithAddSpecialTokens(true),
+		tokenizer.WithMaxLength(6),
+		tokenizer.WithTruncationDirection(tokenizer.TruncateLeft),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"[CLS]", "far", "away", "from", "home", "[SEP]"}
+	if !reflect.DeepEqual(want, en.Tokens) {
+		t.Errorf("Want: %v\nGot: %v\n", want, en.Tokens)
+	}
+}
+
+func TestEncodeWithOffsetType(t *testing.T) {
+	tk := newWordLevelTokenizer(t, []string{"[UNK]", "a", "👍🏽", "b"}, nil)
+	tk.WithPreTokenizer(pretokenizer.NewWhitespaceSplit())
+	sentence := "a 👍🏽 b"
+
+	tests := []struct {
+		offsetType tokenizer.OffsetType
+		want       [][]int
+		pos        int // position inside the emoji
+	}{
+		{tokenizer.Byte, [][]int{{0, 1}, {2, 10}, {11, 12}}, 5},
+		{tokenizer.Char, [][]int{{0, 1}, {2, 4}, {5, 6}}, 3},
+		{tokenizer.UTF16, [][]int{{0, 1}, {2, 6}, {7, 8}}, 5},
+		{tokenizer.Grapheme, [][]int{{0, 1}, {2, 3}, {4, 5}}, 2},
+	}
+
+	for _, tt := range tests {
+		en, err := tk.EncodeSingleWithOptions(sentence, tokenizer.WithOffsetType(tt.offsetType))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, en.Offsets) {
+			t.Errorf("%v: want offsets %v, got %v", tt.offsetType, tt.want, en.Offsets)
+		}
+		if en.OffsetType != tt.offsetType {
+			t.Errorf("%v: got offset type %v", tt.offsetType, en.OffsetType)
+		}
+		if idx, ok := en.Char2Token(tt.pos); !ok || idx != 1 {
+			t.Errorf("%v: want token 1 at position %d, got %d", tt.offsetType, tt.pos, idx)
+		}
+		if word, ok := en.Word2Chars(2); !ok || !reflect.DeepEqual(tt.want[2], word) {
+			t.Errorf("%v: want word offsets %v, got %v", tt.offsetType, tt.want[2], word)
+		}
+	}
+
+	// Offsets not returned are not converted
+	en, err := tk.EncodeSingleWithOptions(sentence, tokenizer.WithOffsetType(tokenizer.Char), tokenizer.WithReturnOffsets(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if en.Offsets != nil || en.OffsetType != tokenizer.Byte {
+		t.Errorf("want no offsets of type Byte, got %v of type %v", en.Offsets, en.OffsetType)
+	}
+}
+
+func TestEncodeBatchWithOptions(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	padding := &tokenizer.PaddingParams{
+		Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithBatchLongest()),
+		Direction: tokenizer.Right,
+		PadToken:  "[PAD]",
+	}
+	encodings, err := tk.EncodeBatchWithOptions(batchInputs("hello", "far far away"),
+		tokenizer.WithAddSpecialTokens(true),
+		tokenizer.WithPaddingParams(padding),
+		tokenizer.WithReturnMasks(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, en := range encodings {
+		if en.Len() != 5 {
+			t.Errorf("input %d: want 5 tokens, got %d", i, en.Len())
+		}
+		if en.AttentionMask != nil || en.SpecialTokenMask != nil {
+			t.Errorf("input %d: masks should not be returned", i)
+		}
+	}
+}
+
+func TestEncodeWithSpecialTokensPolicy(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	tk.AddTokens([]tokenizer.AddedToken{tokenizer.NewAddedToken("foobar", false)})
+
+	tokens := func(text string, opts ...tokenizer.EncodeOption) ([]string, error) {
+		en, err := tk.EncodeSingleWithOptions(text, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return en.Tokens, nil
+	}
+
+	tests := []struct {
+		name string
+		text string
+		opts []tokenizer.EncodeOption
+		want []string
+	}{
+		{"default", "[CLS] hi [SEP] foobar", nil, []string{"[CLS]", "hi", "[SEP]", "foobar"}},
+		{"split", "[CLS] hi [SEP] foobar", []tokenizer.EncodeOption{tokenizer.WithSplitSpecialTokens(true)}, []string{"[", "cl", "##s", "]", "hi", "[", "sep", "]", "foobar"}},
+		{"allowed", "[CLS] hi [SEP]", []tokenizer.EncodeOption{tokenizer.WithAllowedSpecial("[SEP]")}, []string{"[", "cl", "##s", "]", "hi", "[SEP]"}},
+		{"allowed over disallowed", "hi [SEP]", []tokenizer.EncodeOption{tokenizer.WithAllowedSpecial("[SEP]"), tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens)}, []string{"hi", "[SEP]"}},
+		{"not disallowed", "[CLS] hi", []tokenizer.EncodeOption{tokenizer.WithDisallowedSpecial("[SEP]")}, []string{"[CLS]", "hi"}},
+	}
+
+	for _, tt := range tests {
+		got, err := tokens(tt.text, tt.opts...)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("%s: want %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	for _, opts := range [][]tokenizer.EncodeOption{
+		{tokenizer.WithDisallowedSpecial("[SEP]")},
+		{tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens)},
+		{tokenizer.WithAllowedSpecial("[CLS]"), tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens)},
+	} {
+		if _, err := tokens("hi [SEP]", opts...); !errors.Is(err, tokenizer.ErrDisallowedSpecial) {
+			t.Errorf("want ErrDisallowedSpecial, got %v", err)
+		}
+	}
+
+	// Batches report the failing input
+	_, err := tk.EncodeBatchContext(context.Background(), []tokenizer.EncodeInput{
+		tokenizer.NewSingleEncodeInput(tokenizer.NewInputSequence("hi")),
+		tokenizer.NewSingleEncodeInput(tokenizer.NewInputSequence("hi [SEP]")),
+	}, tokenizer.WithEncodeOptions(tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens)))
+	var batchErr *tokenizer.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Errors[0] != nil || !errors.Is(batchErr.Errors[1], tokenizer.ErrDisallowedSpecial) {
+		t.Errorf("want a batch error for the second input, got %v", err)
+	}
+}
+
+func TestEncodeWithSpecialTokensPolicy_Patterns(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	byteTokens := tokenizer.NewAddedTokenPattern("<0x%02X>", 40000, 256, true)
+	byteTokens.Regexp = `(?i)<0x([0-9a-f]{2})>`
+	if err := tk.AddTokenPatterns([]tokenizer.AddedTokenPattern{byteTokens}); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(text string, opts ...tokenizer.EncodeOption) ([]int, error) {
+		en, err := tk.EncodeSingleWithOptions(text, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return en.Ids, nil
+	}
+
+	// Tokens matched by the regexp are special tokens under their canonical content
+	for _, text := range []string{"hi <0x0a>", "hi <0x0A>"} {
+		if _, err := ids(text, tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens)); !errors.Is(err, tokenizer.ErrDisallowedSpecial) {
+			t.Errorf("%q: want ErrDisallowedSpecial, got %v", text, err)
+		}
+		got, err := ids(text, tokenizer.WithSplitSpecialTokens(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range got {
+			if id == 40010 {
+				t.Errorf("%q: want no special token when splitting, got %v", text, got)
+			}
+		}
+		got, err = ids(text, tokenizer.WithAllowedSpecial("<0x0A>"), tokenizer.WithDisallowedSpecial(tokenizer.AllSpecialTokens))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int{7632, 40010}; !reflect.DeepEqual(want, got) {
+			t.Errorf("%q: want %v, got %v", text, want, got)
+		}
+	}
+}
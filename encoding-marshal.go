@@ -0,0 +1,380 @@
+package tokenizer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// encodingJSON is the JSON wire format of Encoding. Field names follow
+// the HuggingFace `Encoding` attributes.
+type encodingJSON struct {
+	Ids              []int             `json:"ids"`
+	TypeIds          []int             `json:"type_ids"`
+	Tokens           []string          `json:"tokens"`
+	Offsets          [][]int           `json:"offsets"`
+	SpecialTokenMask []int             `json:"special_tokens_mask"`
+	AttentionMask    []int             `json:"attention_mask"`
+	Words            []*int            `json:"word_ids"`
+	SequenceRanges   map[int]jsonRange `json:"sequence_ranges"`
+	OffsetType       OffsetType        `json:"offset_type"`
+	Overflowing      []encodingJSON    `json:"overflowing"`
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// Word indexes of -1 (no word) are written as `null`. Contiguous sequence
+// ranges are written as `[start, end)` pairs, other ranges as lists of such
+// pairs and nil ranges as `null`, so that they round-trip as in the binary form.
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	var v encodingJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*e = v.toEncoding()
+	return nil
+}
+
+func (e *Encoding) toJSON() encodingJSON {
+	v := encodingJSON{
+		Ids:              e.Ids,
+		TypeIds:          e.TypeIds,
+		Tokens:           e.Tokens,
+		Offsets:          e.Offsets,
+		SpecialTokenMask: e.SpecialTokenMask,
+		AttentionMask:    e.AttentionMask,
+		OffsetType:       e.OffsetType,
+	}
+
+	if e.Words != nil {
+		v.Words = make([]*int, len(e.Words))
+		for i, w := range e.Words {
+			if w >= 0 {
+				w := w
+				v.Words[i] = &w
+			}
+		}
+	}
+
+	if e.SequenceRanges != nil {
+		v.SequenceRanges = make(map[int]jsonRange, len(e.SequenceRanges))
+		for seqId, r := range e.SequenceRanges {
+			v.SequenceRanges[seqId] = jsonRange(r)
+		}
+	}
+
+	if e.Overflowing != nil {
+		v.Overflowing = make([]encodingJSON, len(e.Overflowing))
+		for i := range e.Overflowing {
+			v.Overflowing[i] = e.Overflowing[i].toJSON()
+		}
+	}
+
+	return v
+}
+
+func (v *encodingJSON) toEncoding() Encoding {
+	e := Encoding{
+		Ids:              v.Ids,
+		TypeIds:          v.TypeIds,
+		Tokens:           v.Tokens,
+		Offsets:          v.Offsets,
+		SpecialTokenMask: v.SpecialTokenMask,
+		AttentionMask:    v.AttentionMask,
+		OffsetType:       v.OffsetType,
+	}
+
+	if v.Words != nil {
+		e.Words = make([]int, len(v.Words))
+		for i, w := range v.Words {
+			e.Words[i] = -1
+			if w != nil {
+				e.Words[i] = *w
+			}
+		}
+	}
+
+	if v.SequenceRanges != nil {
+		e.SequenceRanges = make(map[int]Range, len(v.SequenceRanges))
+		for seqId, r := range v.SequenceRanges {
+			e.SequenceRanges[seqId] = Range(r)
+		}
+	}
+
+	if v.Overflowing != nil {
+		e.Overflowing = make([]Encoding, len(v.Overflowing))
+		for i := range v.Overflowing {
+			e.Overflowing[i] = v.Overflowing[i].toEncoding()
+		}
+	}
+
+	return e
+}
+
+// jsonRange is the JSON form of a Range: a `[start, end)` pair if the range is
+// contiguous, a list of `[start, end)` pairs otherwise, and `null` if nil.
+type jsonRange Range
+
+// MarshalJSON implements json.Marshaler.
+func (r jsonRange) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+
+	// Split the range in contiguous runs
+	runs := [][2]int{}
+	for i, v := range r {
+		if i > 0 && v == r[i-1]+1 {
+			runs[len(runs)-1][1] = v + 1
+			continue
+		}
+		runs = append(runs, [2]int{v, v + 1})
+	}
+	if len(runs) == 1 {
+		return json.Marshal(runs[0])
+	}
+
+	return json.Marshal(runs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *jsonRange) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*r = nil
+		return nil
+	}
+
+	var runs [][2]int
+	if len(raw) > 0 && !strings.HasPrefix(strings.TrimSpace(string(raw[0])), "[") {
+		var bounds [2]int
+		if err := json.Unmarshal(data, &bounds); err != nil {
+			return err
+		}
+		runs = [][2]int{bounds}
+	} else if err := json.Unmarshal(data, &runs); err != nil {
+		return err
+	}
+
+	out := Range{}
+	for _, run := range runs {
+		for v := run[0]; v < run[1]; v++ {
+			out = append(out, v)
+		}
+	}
+	*r = jsonRange(out)
+
+	return nil
+}
+
+// encodingBinaryVersion is the version byte of the binary wire format.
+const encodingBinaryVersion = 1
+
+// ErrInvalidEncodingData is returned when decoding invalid binary encoding data.
+var ErrInvalidEncodingData = errors.New("invalid binary encoding data")
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The binary form is a version byte followed by the fields as varints. Each
+// slice is prefixed by its length plus one, zero meaning a nil slice, so that
+// nil and empty fields round-trip exactly.
+func (e Encoding) MarshalBinary() ([]byte, error) {
+	buf := []byte{encodingBinaryVersion}
+	return e.appendBinary(buf)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *Encoding) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != encodingBinaryVersion {
+		return fmt.Errorf("%w: unsupported version", ErrInvalidEncodingData)
+	}
+
+	d := &binaryDecoder{data: data[1:]}
+	*e = d.encoding()
+	if d.err == nil && len(d.data) > 0 {
+		d.err = fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncodingData, len(d.data))
+	}
+
+	return d.err
+}
+
+func (e *Encoding) appendBinary(buf []byte) ([]byte, error) {
+	buf = appendInts(buf, e.Ids)
+	buf = appendInts(buf, e.TypeIds)
+
+	buf = appendLen(buf, e.Tokens == nil, len(e.Tokens))
+	for _, tok := range e.Tokens {
+		buf = binary.AppendUvarint(buf, uint64(len(tok)))
+		buf = append(buf, tok...)
+	}
+
+	buf = appendLen(buf, e.Offsets == nil, len(e.Offsets))
+	for _, o := range e.Offsets {
+		buf = appendInts(buf, o)
+	}
+
+	buf = appendInts(buf, e.SpecialTokenMask)
+	buf = appendInts(buf, e.AttentionMask)
+	buf = appendInts(buf, e.Words)
+	buf = binary.AppendUvarint(buf, uint64(e.OffsetType))
+
+	// Sequence ranges, sorted by sequence id for a stable output
+	seqIds := make([]int, 0, len(e.SequenceRanges))
+	for seqId := range e.SequenceRanges {
+		seqIds = append(seqIds, seqId)
+	}
+	sort.Ints(seqIds)
+	buf = appendLen(buf, e.SequenceRanges == nil, len(seqIds))
+	for _, seqId := range seqIds {
+		buf = binary.AppendVarint(buf, int64(seqId))
+		buf = appendInts(buf, e.SequenceRanges[seqId])
+	}
+
+	buf = appendLen(buf, e.Overflowing == nil, len(e.Overflowing))
+	for i := range e.Overflowing {
+		var err error
+		if buf, err = e.Overflowing[i].appendBinary(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func appendLen(buf []byte, isNil bool, n int) []byte {
+	if isNil {
+		return binary.AppendUvarint(buf, 0)
+	}
+
+	return binary.AppendUvarint(buf, uint64(n)+1)
+}
+
+func appendInts[T ~[]int](buf []byte, s T) []byte {
+	buf = appendLen(buf, s == nil, len(s))
+	for _, v := range s {
+		buf = binary.AppendVarint(buf, int64(v))
+	}
+
+	return buf
+}
+
+// binaryDecoder reads the binary wire format, keeping the first error.
+type binaryDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *binaryDecoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidEncodingData)
+	}
+	d.data = nil
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+
+	return v
+}
+
+func (d *binaryDecoder) varint() int {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+
+	return int(v)
+}
+
+// length reads a length prefix. It returns -1 for a nil slice.
+func (d *binaryDecoder) length() int {
+	n := d.uvarint()
+	// Every item takes at least one byte
+	if n > uint64(len(d.data))+1 {
+		d.fail()
+		return -1
+	}
+
+	return int(n) - 1
+}
+
+func (d *binaryDecoder) ints() []int {
+	n := d.length()
+	if n < 0 {
+		return nil
+	}
+
+	s := make([]int, n)
+	for i := range s {
+		s[i] = d.varint()
+	}
+
+	return s
+}
+
+func (d *binaryDecoder) encoding() Encoding {
+	var e Encoding
+	e.Ids = d.ints()
+	e.TypeIds = d.ints()
+
+	if n := d.length(); n >= 0 {
+		e.Tokens = make([]string, n)
+		for i := range e.Tokens {
+			size := d.uvarint()
+			if size > uint64(len(d.data)) {
+				d.fail()
+				break
+			}
+			e.Tokens[i] = string(d.data[:size])
+			d.data = d.data[size:]
+		}
+	}
+
+	if n := d.length(); n >= 0 {
+		e.Offsets = make([][]int, n)
+		for i := range e.Offsets {
+			e.Offsets[i] = d.ints()
+		}
+	}
+
+	e.SpecialTokenMask = d.ints()
+	e.AttentionMask = d.ints()
+	e.Words = d.ints()
+	e.OffsetType = OffsetType(d.uvarint())
+
+	if n := d.length(); n >= 0 {
+		e.SequenceRanges = make(map[int]Range, n)
+		for i := 0; i < n; i++ {
+			seqId := d.varint()
+			e.SequenceRanges[seqId] = d.ints()
+		}
+	}
+
+	if n := d.length(); n >= 0 {
+		e.Overflowing = make([]Encoding, n)
+		for i := range e.Overflowing {
+			e.Overflowing[i] = d.encoding()
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,105 @@
+package tokenizer_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func encodeWithOverflowing(t *testing.T) *tokenizer.Encoding {
+	tk := pretrained.BertBaseUncased()
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 10, Strategy: tokenizer.OnlySecond, Stride: 1})
+
+	en, err := tk.EncodePair("who saw a cat", "yesterday i saw a cat far away from home", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(en.Overflowing) == 0 || len(en.SequenceRanges) != 2 {
+		t.Fatalf("expected overflowing and sequence ranges, got %+v", en)
+	}
+
+	return en
+}
+
+func TestEncoding_MarshalJSON(t *testing.T) {
+	want := encodeWithOverflowing(t)
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{`"ids":`, `"type_ids":`, `"offsets":`, `"attention_mask":`, `"word_ids":[null,0,`, `"overflowing":`, `"sequence_ranges":{"0":[1,5]`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("missing %s in %s", field, data)
+		}
+	}
+
+	got := new(tokenizer.Encoding)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %+v\n", want)
+		t.Errorf("Got: %+v\n", got)
+	}
+}
+
+func TestEncoding_MarshalJSON_SequenceRanges(t *testing.T) {
+	want := &tokenizer.Encoding{
+		Ids: []int{1, 2, 3, 4, 5},
+		SequenceRanges: map[int]tokenizer.Range{
+			0: {0, 1, 3, 4},
+			1: {2},
+			2: {},
+			3: nil,
+		},
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if field := `"sequence_ranges":{"0":[[0,2],[3,5]],"1":[2,3],"2":[],"3":null}`; !strings.Contains(string(data), field) {
+		t.Errorf("missing %s in %s", field, data)
+	}
+
+	got := new(tokenizer.Encoding)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want.SequenceRanges, got.SequenceRanges) {
+		t.Errorf("want %#v, got %#v", want.SequenceRanges, got.SequenceRanges)
+	}
+}
+
+func TestEncoding_MarshalBinary(t *testing.T) {
+	want := encodeWithOverflowing(t)
+	want.Words = nil
+	want.AttentionMask = []int{}
+
+	data, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := new(tokenizer.Encoding)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %+v\n", want)
+		t.Errorf("Got: %+v\n", got)
+	}
+
+	for _, n := range []int{0, 1, len(data) / 2, len(data) - 1} {
+		if err := got.UnmarshalBinary(data[:n]); !errors.Is(err, tokenizer.ErrInvalidEncodingData) {
+			t.Errorf("truncated data (%d bytes): want ErrInvalidEncodingData, got %v", n, err)
+		}
+	}
+}
@@ -25,6 +25,7 @@ type Encoding struct {
 	Overflowing      []Encoding    // A list of overflowing generated when being truncated
 	Words            []int         // Optional - Indexes of the word associated with each token/ID. None value = -1
 	SequenceRanges   map[int]Range // Range of tokens covered by each sequence. If empty -> only one sequence and covers the entire range.
+	OffsetType       OffsetType    // Unit of `Offsets`: bytes, chars, UTF-16 code units or graphemes
 }
 
 type EncodingOpts struct {
@@ -69,6 +70,7 @@ func NewEncoding(ids []int, typeIds []int, tokens []string, offsets [][]int, spe
 		overflowing,
 		o.Words,
 		o.SequenceRange,
+		Byte,
 	}
 }
 
@@ -188,6 +190,22 @@ func (e *Encoding) SetSequenceIds(sequenceId int) {
 	}
 }
 
+// setOffsetType sets the unit of offsets for this Encoding and its overflowing.
+func (e *Encoding) setOffsetType(offsetType OffsetType) {
+	e.OffsetType = offsetType
+	for i := range e.Overflowing {
+		e.Overflowing[i].setOffsetType(offsetType)
+	}
+}
+
+// setSequenceIds sets the given sequence id for this Encoding and its overflowing.
+func (e *Encoding) setSequenceIds(sequenceId int) {
+	e.SetSequenceIds(sequenceId)
+	for i := range e.Overflowing {
+		e.Overflowing[i].SetSequenceIds(sequenceId)
+	}
+}
+
 func (e *Encoding) GetSequenceIds() []int {
 	sequences := make([]int, e.Len())
 	for seqId := 0; seqId < e.NSequences(); seqId++ {
@@ -300,8 +318,10 @@ func (e *Encoding) Word2Chars(word int) (retVal []int, ok bool) {
 }
 
 // Token2Chars get the offsets of the token at the given index
+//
+// Offsets are in the unit the encoding was produced in (see `OffsetType`).
 func (e *Encoding) Token2Chars(tokenIdx int) (retVal []int, ok bool) {
-	if tokenIdx < 0 || tokenIdx > len(e.Offsets) {
+	if tokenIdx < 0 || tokenIdx >= len(e.Offsets) {
 		return retVal, false
 	} else {
 		return e.Offsets[tokenIdx], true
@@ -320,6 +340,9 @@ func (e *Encoding) Token2Word(tokenIdx int) (retVal int, ok bool) {
 }
 
 // Char2Token returns a token index that contains the given `char` index
+//
+// `pos` is in the unit the encoding was produced in (see `OffsetType`), e.g.
+// a UTF-16 code unit index for an encoding produced with `UTF16` offsets.
 func (e *Encoding) Char2Token(pos int) (retVal int, ok bool) {
 	for i, o := range e.Offsets {
 		if pos >= o[0] && pos < o[1] {
@@ -341,7 +364,16 @@ func (e *Encoding) Char2Word(pos int) (retVal int, ok bool) {
 
 // Truncate truncates the current encoding
 func (e *Encoding) Truncate(maxLen int, stride int) (retVal *Encoding, err error) {
+	return e.TruncateWithDirection(maxLen, stride, TruncateRight)
+}
 
+// TruncateWithDirection truncates the current encoding to `maxLen` tokens.
+// `TruncateRight` keeps the first tokens and `TruncateLeft` keeps the last ones.
+//
+// The removed tokens are put in `Overflowing` encodings of at most `maxLen`
+// tokens, going away from the kept part. Each of them overlaps the previous
+// one by `stride` tokens.
+func (e *Encoding) TruncateWithDirection(maxLen, stride int, direction TruncationDirection) (retVal *Encoding, err error) {
 	if stride >= maxLen || maxLen == 0 {
 		return retVal, fmt.Errorf("Invalid input maxLen or stride (stride must be less than maxLen and maxLen must be greater than zero.)")
 	}
@@ -351,60 +383,90 @@ func (e *Encoding) Truncate(maxLen int, stride int) (retVal *Encoding, err error
 		return e, nil
 	}
 
-	// Truncating at maxLen (exclusive) to keep.
-	// The rest (overflowing) from maxLen (inclusive)
-	newIds := e.Ids[0:maxLen]
-	oIds := e.Ids[maxLen:len(e.Ids)] // overflowing
-	newTypeIds := e.TypeIds[0:maxLen]
-	oTypeIds := e.TypeIds[maxLen:len(e.TypeIds)]
-	newTokens := e.Tokens[0:maxLen]
-	oTokens := e.Tokens[maxLen:len(e.Tokens)]
-	newOffsets := e.Offsets[0:maxLen]
-	oOffsets := e.Offsets[maxLen:len(e.Offsets)]
-	newSpeToks := e.SpecialTokenMask[0:maxLen]
-	oSpeToks := e.SpecialTokenMask[maxLen:len(e.SpecialTokenMask)]
-	newAttent := e.AttentionMask[0:maxLen]
-	oAttent := e.AttentionMask[maxLen:len(e.AttentionMask)]
-	newWords := e.Words[0:maxLen]
-	oWords := e.Words[maxLen:len(e.Words)]
-
-	e.Ids = newIds
-	e.TypeIds = newTypeIds
-	e.Tokens = newTokens
-	e.Offsets = newOffsets
-	e.SpecialTokenMask = newSpeToks
-	e.AttentionMask = newAttent
-	e.Words = newWords
+	parts := truncationParts(len(e.Ids), maxLen, stride, direction)
 
 	// Separate the overflowing part into as many Encoding as needed
-	partSize := maxLen - stride
-	overflowing := make([]Encoding, 0)
-	partId := 0
-	prevEncoding := e
-
-	// while loop
-	for int(partSize)*partId < len(oIds) {
-		o := Encoding{
-			Ids:              getCurrentPart(prevEncoding.Ids, oIds, partSize, partId, stride),
-			TypeIds:          getCurrentPart(prevEncoding.TypeIds, oTypeIds, partSize, partId, stride),
-			Tokens:           getCurrentPart(prevEncoding.Tokens, oTokens, partSize, partId, stride),
-			Offsets:          getCurrentPart(prevEncoding.Offsets, oOffsets, partSize, partId, stride),
-			SpecialTokenMask: getCurrentPart(prevEncoding.SpecialTokenMask, oSpeToks, partSize, partId, stride),
-			AttentionMask:    getCurrentPart(prevEncoding.AttentionMask, oAttent, partSize, partId, stride),
-			Words:            getCurrentPart(prevEncoding.Words, oWords, partSize, partId, stride),
-			Overflowing:      make([]Encoding, 0),
-		}
-
-		partId += 1
+	overflowing := make([]Encoding, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		o := e.slice(p[0], p[1])
+		o.Overflowing = make([]Encoding, 0)
 		overflowing = append(overflowing, o)
-		prevEncoding = &overflowing[len(overflowing)-1]
 	}
 
+	kept := e.slice(parts[0][0], parts[0][1])
+	e.Ids = kept.Ids
+	e.TypeIds = kept.TypeIds
+	e.Tokens = kept.Tokens
+	e.Offsets = kept.Offsets
+	e.SpecialTokenMask = kept.SpecialTokenMask
+	e.AttentionMask = kept.AttentionMask
+	e.Words = kept.Words
 	e.Overflowing = overflowing
 
 	return e, nil
 }
 
+// truncationParts returns the [start, stop) token ranges of the kept part
+// (first) and the overflowing parts when truncating `n` tokens.
+func truncationParts(n, maxLen, stride int, direction TruncationDirection) [][2]int {
+	var (
+		parts  [][2]int
+		offset = maxLen - stride
+	)
+
+	switch direction {
+	case TruncateLeft:
+		for stop := n; ; stop -= offset {
+			start := stop - maxLen
+			if start < 0 {
+				start = 0
+			}
+			parts = append(parts, [2]int{start, stop})
+			if start == 0 {
+				break
+			}
+		}
+	default:
+		for start := 0; ; start += offset {
+			stop := start + maxLen
+			if stop > n {
+				stop = n
+			}
+			parts = append(parts, [2]int{start, stop})
+			if stop == n {
+				break
+			}
+		}
+	}
+
+	return parts
+}
+
+// slice returns a copy of the tokens in range [start, stop), without overflowing
+// and sequence ranges.
+func (e *Encoding) slice(start, stop int) Encoding {
+	return Encoding{
+		Ids:              subSlice(e.Ids, start, stop),
+		TypeIds:          subSlice(e.TypeIds, start, stop),
+		Tokens:           subSlice(e.Tokens, start, stop),
+		Offsets:          subSlice(e.Offsets, start, stop),
+		SpecialTokenMask: subSlice(e.SpecialTokenMask, start, stop),
+		AttentionMask:    subSlice(e.AttentionMask, start, stop),
+		Words:            subSlice(e.Words, start, stop),
+		OffsetType:       e.OffsetType,
+	}
+}
+
+// subSlice copies items in range [start, stop). It returns nil if the slice
+// is too short, i.e. for optional fields that have not been set.
+func subSlice[T any](s []T, start, stop int) []T {
+	if len(s) < stop {
+		return nil
+	}
+
+	return append([]T{}, s[start:stop]...)
+}
+
 // Merge merges all Encodings together
 func (e *Encoding) Merge(encodings []Encoding, growingOffsets bool) (retVal *Encoding) {
 	retVal = e
@@ -455,14 +517,18 @@ func (e *Encoding) MergeWith(pair *Encoding, growingOffsets bool) (retVal *Encod
 	// Merging others
 	originalLen := e.Len()
 	if len(pair.SequenceRanges) > 0 {
+		// Don't modify a map that may be shared with the pair encoding.
+		sequenceRanges := make(map[int]Range, len(e.SequenceRanges)+len(pair.SequenceRanges))
+		for seqId, r := range e.SequenceRanges {
+			sequenceRanges[seqId] = r
+		}
 		for seqId, r := range pair.SequenceRanges {
 			start := originalLen + r[0]
 			end := originalLen + r[r.Len()-1] + 1
 			newRange := NewRange(start, end)
-			var oldRange Range
-			util.DeepCopy(e.SequenceRanges[seqId], oldRange)
-			e.SequenceRanges[seqId] = util.Merge(oldRange, newRange)
+			sequenceRanges[seqId] = util.Merge(sequenceRanges[seqId], newRange)
 		}
+		e.SequenceRanges = sequenceRanges
 	}
 
 	e.Ids = util.Merge(e.Ids, pair.Ids)
@@ -555,8 +621,10 @@ func (e *Encoding) Pad(targetLength, padId, padTypeId int, padToken string, dire
 	// 1. Overflowing
 	var overflowing []Encoding
 	for _, o := range e.Overflowing {
-		padded := o.pad(targetLength, padId, padTypeId, padToken, direction)
-		overflowing = append(overflowing, *padded)
+		if len(o.Ids) < targetLength {
+			o = *o.pad(targetLength, padId, padTypeId, padToken, direction)
+		}
+		overflowing = append(overflowing, o)
 	}
 	e.Overflowing = overflowing
 
@@ -586,7 +654,7 @@ func (e *Encoding) pad(targetLength, padId, padTypeId int, padToken string, dire
 		for i := 0; i < len(newTypeIds); i++ {
 			newTypeIds[i] = padTypeId
 		}
-		newTypeIds = append(newTypeIds, e.Ids...)
+		newTypeIds = append(newTypeIds, e.TypeIds...)
 		e.TypeIds = newTypeIds
 
 		newTokens := make([]string, padLength)
@@ -611,7 +679,7 @@ func (e *Encoding) pad(targetLength, padId, padTypeId int, padToken string, dire
 		e.AttentionMask = newAttentionMask
 
 		newOffsets := make([][]int, padLength)
-		for i := 0; i < len(newIds); i++ {
+		for i := 0; i < len(newOffsets); i++ {
 			newOffsets[i] = []int{0, 0}
 		}
 		newOffsets = append(newOffsets, e.Offsets...)
@@ -624,6 +692,20 @@ func (e *Encoding) pad(targetLength, padId, padTypeId int, padToken string, dire
 		newWords = append(newWords, e.Words...)
 		e.Words = newWords
 
+		// Shift the sequence ranges by the padding length, in a new map as the
+		// map may be shared with copies of the encoding
+		if e.SequenceRanges != nil {
+			sequenceRanges := make(map[int]Range, len(e.SequenceRanges))
+			for seqId, r := range e.SequenceRanges {
+				shifted := make(Range, len(r))
+				for i, idx := range r {
+					shifted[i] = idx + padLength
+				}
+				sequenceRanges[seqId] = shifted
+			}
+			e.SequenceRanges = sequenceRanges
+		}
+
 	case Right:
 		for i := 0; i < padLength; i++ {
 			e.Ids = append(e.Ids, padId)
@@ -665,30 +747,5 @@ func (e *Encoding) SequenceRange(sequencId int) (Range, error) {
 		return nil, err
 	}
 
-	return r[0:e.Len()], nil
-}
-
-func getCurrentPart[T any](previous, current []T, size, idx, stride int) []T {
-	if size <= 0 || idx < 0 || stride < 0 {
-		return nil
-	}
-
-	if len(previous) < stride {
-		stride = len(previous)
-	}
-
-	start := idx * size
-	if start >= len(current) {
-		return previous[len(previous)-stride:]
-	}
-
-	end := (idx + 1) * size
-	if end > len(current) {
-		end = len(current)
-	}
-
-	curr := current[start:end]
-	prev := previous[len(previous)-stride:]
-
-	return append(prev, curr...)
+	return r, nil
 }
@@ -105,6 +105,80 @@ func TestTokenizer_Truncate(t *testing.T) {
 	}
 }
 
+func TestTokenizer_TruncateLeft(t *testing.T) {
+	a := tokenizer.Encoding{
+		Ids:              []int{1, 2, 3, 4, 5},
+		TypeIds:          []int{0, 0, 0, 0, 0},
+		Tokens:           []string{"a", "b", "c", "d", "e"},
+		Offsets:          [][]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}},
+		SpecialTokenMask: []int{0, 0, 0, 0, 0},
+		AttentionMask:    []int{1, 1, 1, 1, 1},
+		Overflowing:      make([]tokenizer.Encoding, 0),
+	}
+
+	got, err := a.TruncateWithDirection(3, 1, tokenizer.TruncateLeft)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"c", "d", "e"}; !reflect.DeepEqual(want, got.Tokens) {
+		t.Errorf("Want: %v\nGot: %v\n", want, got.Tokens)
+	}
+
+	var overflowing [][]string
+	for _, o := range got.Overflowing {
+		overflowing = append(overflowing, o.Tokens)
+	}
+	if want := [][]string{{"a", "b", "c"}}; !reflect.DeepEqual(want, overflowing) {
+		t.Errorf("Want overflowing: %v\nGot: %v\n", want, overflowing)
+	}
+	if want := [][]int{{0, 1}, {1, 2}, {2, 3}}; !reflect.DeepEqual(want, got.Overflowing[0].Offsets) {
+		t.Errorf("Want offsets: %v\nGot: %v\n", want, got.Overflowing[0].Offsets)
+	}
+}
+
+func TestTokenizer_PadLeft(t *testing.T) {
+	a := tokenizer.NewEncoding([]int{1, 2}, []int{0, 1}, []string{"a", "b"}, [][]int{{0, 1}, {1, 2}}, []int{0, 0}, []int{1, 1}, nil,
+		tokenizer.WithWordsEncodingOpt([]int{0, 1}),
+		tokenizer.WithSequenceRangeEncodingOpt(map[int]tokenizer.Range{0: {0}, 1: {1}}),
+	)
+
+	got := a.Pad(4, 9, 7, "[PAD]", tokenizer.Left)
+
+	if want := []int{7, 7, 0, 1}; !reflect.DeepEqual(want, got.TypeIds) {
+		t.Errorf("Want type ids: %v\nGot: %v\n", want, got.TypeIds)
+	}
+	if want := [][]int{{0, 0}, {0, 0}, {0, 1}, {1, 2}}; !reflect.DeepEqual(want, got.Offsets) {
+		t.Errorf("Want offsets: %v\nGot: %v\n", want, got.Offsets)
+	}
+	if want := map[int]tokenizer.Range{0: {2}, 1: {3}}; !reflect.DeepEqual(want, got.SequenceRanges) {
+		t.Errorf("Want sequence ranges: %v\nGot: %v\n", want, got.SequenceRanges)
+	}
+}
+
+func TestPadEncodings_LeftKeepsInputs(t *testing.T) {
+	a := tokenizer.NewEncoding([]int{1, 2}, []int{0, 0}, []string{"a", "b"}, [][]int{{0, 1}, {1, 2}}, []int{0, 0}, []int{1, 1}, nil,
+		tokenizer.WithSequenceRangeEncodingOpt(map[int]tokenizer.Range{0: {0, 1}}),
+	)
+	inputs := []tokenizer.Encoding{*a}
+
+	got := tokenizer.PadEncodings(inputs, tokenizer.PaddingParams{
+		Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithFixed(4)),
+		Direction: tokenizer.Left,
+		PadToken:  "[PAD]",
+	})
+
+	if want := map[int]tokenizer.Range{0: {2, 3}}; !reflect.DeepEqual(want, got[0].SequenceRanges) {
+		t.Errorf("Want sequence ranges: %v\nGot: %v\n", want, got[0].SequenceRanges)
+	}
+	if want := map[int]tokenizer.Range{0: {0, 1}}; !reflect.DeepEqual(want, inputs[0].SequenceRanges) {
+		t.Errorf("Want input sequence ranges: %v\nGot: %v\n", want, inputs[0].SequenceRanges)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(want, inputs[0].Ids) {
+		t.Errorf("Want input ids: %v\nGot: %v\n", want, inputs[0].Ids)
+	}
+}
+
 func TestTokenizer_Mapping(t *testing.T) {
 	encoding := tokenizer.DefaultEncoding()
 	encoding.Tokens = []string{"He", "llo", "won", "der", "ful", "friend", "!"}
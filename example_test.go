@@ -1,9 +1,11 @@
 package tokenizer_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/sugarme/tokenizer"
 	"github.com/sugarme/tokenizer/pretrained"
 )
 
@@ -45,3 +47,45 @@ func ExampleTokenizer_EncodeSingle() {
 	// offsets: [[0 0] [0 5] [5 6] [7 8] [8 9] [9 12] [12 13] [14 17] [18 21] [22 25] [26 30] [31 32] [0 0]]
 	// word Ids: [-1 0 1 2 3 4 5 6 7 8 9 10 -1]
 }
+
+func ExampleTokenizer_EncodeBatchOverflowing() {
+	tk := pretrained.BertBaseUncased()
+
+	// Only the context (second sequence) is truncated, into windows of
+	// 12 tokens overlapping by 2 tokens.
+	tk.WithTruncation(&tokenizer.TruncationParams{
+		MaxLength: 12,
+		Strategy:  tokenizer.OnlySecond,
+		Stride:    2,
+	})
+
+	question := "who saw a cat"
+	contexts := []string{
+		"yesterday i saw a cat far away from home",
+		"a dog",
+	}
+
+	var inputs []tokenizer.EncodeInput
+	for _, c := range contexts {
+		inputs = append(inputs, tokenizer.NewDualEncodeInput(tokenizer.NewInputSequence(question), tokenizer.NewInputSequence(c)))
+	}
+
+	rows, overflowToSample, err := tk.EncodeBatchOverflowing(context.Background(), inputs, tokenizer.WithBatchAddSpecialTokens(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, row := range rows {
+		contextRange, err := row.SequenceRange(1)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("sample %d: %v context tokens %v\n", overflowToSample[i], row.GetTokens(), contextRange)
+	}
+
+	// Output:
+	// sample 0: [[CLS] who saw a cat [SEP] yesterday i saw a cat [SEP]] context tokens [6 7 8 9 10]
+	// sample 0: [[CLS] who saw a cat [SEP] a cat far away from [SEP]] context tokens [6 7 8 9 10]
+	// sample 0: [[CLS] who saw a cat [SEP] away from home [SEP]] context tokens [6 7 8]
+	// sample 1: [[CLS] who saw a cat [SEP] a dog [SEP]] context tokens [6 7]
+}
@@ -492,6 +492,74 @@ func (b BPE) TokenizeWithCache(sequence string) (retVal []tokenizer.Token) {
 	}
 }
 
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider.
+func (b BPE) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	if b.UnkToken != nil {
+		roles[tokenizer.UnkRole] = *b.UnkToken
+	}
+
+	return roles
+}
+
+// Clone implements tokenizer.Cloner. The clone starts with an empty cache.
+func (b *BPE) Clone() tokenizer.Model {
+	clone := *b
+	if b.Vocab != nil {
+		vocab := make(model.Vocab, len(*b.Vocab))
+		for k, v := range *b.Vocab {
+			vocab[k] = v
+		}
+		clone.Vocab = &vocab
+	}
+	if b.VocabR != nil {
+		vocabR := make(model.VocabR, len(*b.VocabR))
+		for k, v := range *b.VocabR {
+			vocabR[k] = v
+		}
+		clone.VocabR = &vocabR
+	}
+	if b.Merges != nil {
+		merges := make(Merges, len(*b.Merges))
+		for k, v := range *b.Merges {
+			merges[k] = v
+		}
+		clone.Merges = &merges
+	}
+	if b.Cache != nil {
+		clone.Cache = NewCache(b.Cache.Capacity)
+	}
+
+	return &clone
+}
+
+// CountTokens implements tokenizer.TokenCounter. It merges the sequence like
+// `Tokenize`, sharing its cache, but doesn't build the tokens.
+func (b BPE) CountTokens(sequence string) (int, error) {
+	if len(sequence) == 0 {
+		return 0, nil
+	}
+
+	if b.Dropout != nil {
+		return len(b.MergeWord(sequence).Symbols), nil
+	}
+
+	if b.Cache != nil {
+		if hit, ok := b.Cache.Get(sequence); ok {
+			return len(hit.Symbols), nil
+		}
+	}
+
+	word := b.MergeWord(sequence)
+	if b.Cache != nil {
+		b.Cache.SetValues([]CacheItem{
+			{sequence, *word},
+		})
+	}
+
+	return len(word.Symbols), nil
+}
+
 func (b BPE) TokenToId(token string) (id int, ok bool) {
 	id, ok = (*b.Vocab)[token]
 	return id, ok
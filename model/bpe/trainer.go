@@ -6,7 +6,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
-	"time"
+	"unicode/utf8"
 
 	"github.com/emirpasic/gods/trees/binaryheap"
 	"github.com/emirpasic/gods/utils"
@@ -16,6 +16,7 @@ import (
 	// progressbar "github.com/cheggaaa/pb/v3"
 
 	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretokenizer"
 )
 
 // Map with no value
@@ -28,7 +29,17 @@ type TMerge struct {
 	Pair  Pair
 	Count int
 	Pos   UintSet
-	Time  time.Time
+}
+
+// ByteLevelAlphabet returns the 256 `chars` used by the `ByteLevel`
+// pre-tokenizer to represent every possible byte.
+func ByteLevelAlphabet() CharSet {
+	alphabet := make(CharSet, len(pretokenizer.BytesChar))
+	for _, c := range pretokenizer.BytesChar {
+		alphabet[c] = struct{}{}
+	}
+
+	return alphabet
 }
 
 // NOTE: there exists `Config`
@@ -41,6 +52,7 @@ type TConfig struct {
 	InitialAlphabet         CharSet
 	ContinuingSubwordPrefix *string
 	EndOfWordSuffix         *string
+	MaxTokenLength          *int
 }
 
 // BpeTrainerBuilder can be used to create a `BpeTrainer`
@@ -59,6 +71,7 @@ func NewBPETrainerBuilder() *BpeTrainerBuilder {
 		InitialAlphabet:         nil,
 		ContinuingSubwordPrefix: nil,
 		EndOfWordSuffix:         nil,
+		MaxTokenLength:          nil,
 	}
 	return &BpeTrainerBuilder{
 		Config: &config,
@@ -95,6 +108,17 @@ func (btb *BpeTrainerBuilder) InitialAlphabet(alphabet CharSet) {
 	btb.Config.InitialAlphabet = alphabet
 }
 
+// ByteLevelAlphabet adds the full 256-char `ByteLevel` alphabet to the
+// initial alphabet so that every byte is covered by the trained vocabulary.
+func (btb *BpeTrainerBuilder) ByteLevelAlphabet() {
+	if btb.Config.InitialAlphabet == nil {
+		btb.Config.InitialAlphabet = make(CharSet)
+	}
+	for c := range ByteLevelAlphabet() {
+		btb.Config.InitialAlphabet[c] = struct{}{}
+	}
+}
+
 // ContinuingSubwordPrefix set the ContinuingSubwordPrefix
 func (btb *BpeTrainerBuilder) ContinuingSubwordPrefix(prefix string) {
 	btb.Config.ContinuingSubwordPrefix = &prefix
@@ -105,6 +129,11 @@ func (btb *BpeTrainerBuilder) EndOfWordSuffix(suffix string) {
 	btb.Config.EndOfWordSuffix = &suffix
 }
 
+// MaxTokenLength set the maximum length (in chars) of a merged token
+func (btb *BpeTrainerBuilder) MaxTokenLength(length int) {
+	btb.Config.MaxTokenLength = &length
+}
+
 // Build constructs the final BpeTrainer
 func (btb *BpeTrainerBuilder) Build() *BpeTrainer {
 	return &BpeTrainer{
@@ -116,6 +145,7 @@ func (btb *BpeTrainerBuilder) Build() *BpeTrainer {
 		InitialAlphabet:         btb.Config.InitialAlphabet,
 		ContinuingSubwordPrefix: btb.Config.ContinuingSubwordPrefix,
 		EndOfWordSuffix:         btb.Config.EndOfWordSuffix,
+		MaxTokenLength:          btb.Config.MaxTokenLength,
 	}
 }
 
@@ -131,6 +161,10 @@ func (btb *BpeTrainerBuilder) Build() *BpeTrainer {
 //
 // trainer := NewBPETrainer()
 // model, specialTokens := trainer.Train(wordCounts)
+//
+// Training is deterministic: pairs with equal counts are merged in
+// ascending order of their ids, so training the same word counts twice
+// produces identical vocab and merges.
 type BpeTrainer struct {
 	// The minimum frequency a pair must have to produce a merge operation
 	MinFrequency int
@@ -150,6 +184,8 @@ type BpeTrainer struct {
 	ContinuingSubwordPrefix *string
 	// An optional suffix to characterize and end-of-word subword
 	EndOfWordSuffix *string
+	// An optional maximum length (in chars) of any merged token
+	MaxTokenLength *int
 }
 
 func NewBpeTrainer(minFreq int, vocabSize int) *BpeTrainer {
@@ -208,13 +244,8 @@ func (bt *BpeTrainer) computeAlphabet(wc map[string]int) (wordToId map[string]in
 	for word, count := range wc {
 		chars := strings.Split(word, "")
 		for _, char := range chars {
-			// if char not existing, newCount will be zero
-			if newCount, ok := alphabet[char]; ok {
-				newCount += count
-				alphabet[char] = newCount
-			} else {
-				alphabet[char] = newCount
-			}
+			// if char not existing, it will start from zero
+			alphabet[char] += count
 		}
 	}
 
@@ -257,13 +288,12 @@ func (bt *BpeTrainer) computeAlphabet(wc map[string]int) (wordToId map[string]in
 	// remove the unwanted `chars`
 	if toRemove > 0 {
 		fmt.Println("We are going to remove some chars...")
-		// 1. Sort `kept` by char alphabetically?
-		// TODO: double-check this (sort by char or freq? asc or desc)
-		sort.Slice(kept, func(i, j int) bool {
-			return kept[i].Char < kept[j].Char
+		// 1. Sort `kept` by frequency ascending, ties broken by char
+		sort.SliceStable(kept, func(i, j int) bool {
+			return kept[i].Freq < kept[j].Freq
 		})
-		// 2. Remove the unwanted chars
-		kept = kept[:toRemove]
+		// 2. Remove the least frequent chars
+		kept = kept[toRemove:]
 	}
 
 	// // Keep the initial alphabet (sorted by determinism)
@@ -318,25 +348,18 @@ func (bt *BpeTrainer) tokenizeWords(wc map[string]int, w2id map[string]int, id2w
 		chars := strings.Split(word, "")
 
 		for i, c := range chars {
-			var s string
+			s := c
 			if _, ok := w2id[c]; ok {
 				// Add the `continuingSubwordPrefix` if relevant
-				if i == 0 { // first `char`
+				if i > 0 { // not the first `char`
 					if prefix := bt.ContinuingSubwordPrefix; prefix != nil {
-						s = fmt.Sprintf("%v%v", &prefix, c)
-					} else {
-						s = c
+						s = fmt.Sprintf("%v%v", *prefix, s)
 					}
 				}
-				if i > 0 && i < len(chars)-1 {
-					s = c
-				}
 				// Add the `endOfWordSuffix` if relevant
 				if i == len(chars)-1 { // last `char`
 					if suffix := bt.EndOfWordSuffix; suffix != nil {
-						s = fmt.Sprintf("%v%v", &suffix, c)
-					} else {
-						s = c
+						s = fmt.Sprintf("%v%v", s, *suffix)
 					}
 				}
 
@@ -427,7 +450,7 @@ func (bt *BpeTrainer) countPairs(words []Word, counts []int, progress interface{
 
 				// fmt.Printf("Word: %v\n", word)
 				var window = 2
-				for x := 0; i < len(word.Symbols)-1; x += window - 1 {
+				for x := 0; x < len(word.Symbols)-1; x += window - 1 {
 					y := x + window
 					if y > len(word.Symbols) {
 						// TODO: should we stop when last chunk < chunk size or we just return it
@@ -489,7 +512,13 @@ func (bt *BpeTrainer) countPairs(words []Word, counts []int, progress interface{
 			}
 
 			for pair, hashSet := range res.WT {
-				whereToUpdate[pair] = hashSet
+				if h, ok := whereToUpdate[pair]; ok {
+					for k := range hashSet {
+						h[k] = struct{}{}
+					}
+				} else {
+					whereToUpdate[pair] = hashSet
+				}
 			}
 		}
 
@@ -652,16 +681,15 @@ func (bt *BpeTrainer) train(wordCounts map[string]int) (BPE, []tokenizer.AddedTo
 	// 5. Do merges
 	fmt.Printf("5. Merging pairs from top count down...\n")
 
-	// countComparator sort heap descendingly by `Count` field of TMerge struct
+	// countComparator sort heap descendingly by `Count` field of TMerge struct.
+	// Ties are broken by the pair itself (lowest first) so that training
+	// does not depend on map iteration order.
 	countComparator := func(a, b interface{}) int {
 		c1 := a.(TMerge).Count
 		c2 := b.(TMerge).Count
 
 		if c1 == c2 {
-			aTime := a.(TMerge).Time
-			bTime := b.(TMerge).Time
-
-			return utils.TimeComparator(bTime, aTime)
+			return comparePair(a.(TMerge).Pair, b.(TMerge).Pair)
 		}
 
 		return utils.IntComparator(c2, c1)
@@ -670,7 +698,8 @@ func (bt *BpeTrainer) train(wordCounts map[string]int) (BPE, []tokenizer.AddedTo
 	var queue = binaryheap.NewWith(countComparator)
 
 	// insert them to the queue
-	for pair, pos := range whereToUpdate {
+	for _, pair := range sortedPairs(whereToUpdate) {
+		pos := whereToUpdate[pair]
 		if count, ok := pairCounts[pair]; ok {
 			// char1 := idToWord[pair.C1]
 			// char2 := idToWord[pair.C2]
@@ -774,6 +803,11 @@ func (bt *BpeTrainer) train(wordCounts map[string]int) (BPE, []tokenizer.AddedTo
 		newToken := fmt.Sprintf("%v%v", partA, partB)
 		// fmt.Printf("new token: %v\n", newToken)
 
+		// Skip the merge if the new token would be too long
+		if max := bt.MaxTokenLength; max != nil && utf8.RuneCountInString(newToken) > *max {
+			continue
+		}
+
 		// Insert new token
 		newTokenId := len(idToWord)
 		idToWord = append(idToWord, newToken)
@@ -836,14 +870,15 @@ func (bt *BpeTrainer) train(wordCounts map[string]int) (BPE, []tokenizer.AddedTo
 		// fmt.Printf("length of whereToUpdate: %v\n", len(whereToUpdate))
 		// fmt.Println(whereToUpdate)
 
-		for pair, pos := range whereToUpdate {
+		for _, pair := range sortedPairs(whereToUpdate) {
+			pos := whereToUpdate[pair]
 			count := pairCounts[pair]
 			// char1 := idToWord[pair.C1]
 			// char2 := idToWord[pair.C2]
 			// fmt.Printf("pair chars: '%v%v' - pair: %v - count: %v - pos: %v\n", char1, char2, pair, count, pos)
 			if count > 0 {
 				queue.Push(TMerge{
-					pair, count, pos, time.Now(),
+					pair, count, pos,
 				})
 			}
 		}
@@ -903,3 +938,25 @@ func sortedKeys(m map[string]int) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+// comparePair orders pairs by their first then second id.
+func comparePair(a, b Pair) int {
+	if a.C1 != b.C1 {
+		return utils.IntComparator(a.C1, b.C1)
+	}
+
+	return utils.IntComparator(a.C2, b.C2)
+}
+
+// sortedPairs returns the pairs of the given map in ascending order.
+func sortedPairs(m map[Pair]UintSet) []Pair {
+	pairs := make([]Pair, 0, len(m))
+	for pair := range m {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return comparePair(pairs[i], pairs[j]) < 0
+	})
+
+	return pairs
+}
@@ -1,6 +1,9 @@
 package bpe_test
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
@@ -88,3 +91,80 @@ func sortedKeys(m map[string]int) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+func trainAndSave(t *testing.T, trainer *bpe.BpeTrainer, wordCounts map[string]int) (vocab, merges []byte) {
+	model, _ := trainer.Train(wordCounts)
+
+	dir := t.TempDir()
+	if err := model.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	vocab, err := os.ReadFile(filepath.Join(dir, "vocab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merges, err = os.ReadFile(filepath.Join(dir, "merges.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return vocab, merges
+}
+
+func TestBpeTrainer_Deterministic(t *testing.T) {
+	// Many pairs share the same count so that ties must be broken.
+	wordCounts := map[string]int{
+		"ab": 3, "cd": 3, "ef": 3, "gh": 3, "ij": 3,
+		"abcd": 2, "efgh": 2, "ghij": 2, "hello": 1, "world": 1,
+	}
+
+	btb := bpe.NewBPETrainerBuilder()
+	btb.VocabSize(40)
+	btb.ContinuingSubwordPrefix("##")
+	trainer := btb.Build()
+
+	vocab1, merges1 := trainAndSave(t, trainer, wordCounts)
+	for i := 0; i < 5; i++ {
+		vocab2, merges2 := trainAndSave(t, trainer, wordCounts)
+		if !bytes.Equal(vocab1, vocab2) {
+			t.Fatalf("vocab differs between runs:\n%s\n%s", vocab1, vocab2)
+		}
+		if !bytes.Equal(merges1, merges2) {
+			t.Fatalf("merges differ between runs:\n%s\n%s", merges1, merges2)
+		}
+	}
+}
+
+func TestBpeTrainer_MaxTokenLength(t *testing.T) {
+	wordCounts := map[string]int{"aaaaaa": 5, "abab": 3}
+
+	btb := bpe.NewBPETrainerBuilder()
+	btb.VocabSize(100)
+	btb.MaxTokenLength(2)
+	trainer := btb.Build()
+
+	model, _ := trainer.Train(wordCounts)
+	for tok := range model.GetVocab() {
+		if len([]rune(tok)) > 2 {
+			t.Errorf("token %q exceeds max token length", tok)
+		}
+	}
+	if _, ok := model.TokenToId("aa"); !ok {
+		t.Errorf("expected merged token 'aa' in vocab")
+	}
+}
+
+func TestBpeTrainer_ByteLevelAlphabet(t *testing.T) {
+	btb := bpe.NewBPETrainerBuilder()
+	btb.VocabSize(300)
+	btb.ByteLevelAlphabet()
+	trainer := btb.Build()
+
+	model, _ := trainer.Train(map[string]int{"hello": 1})
+	for c := range bpe.ByteLevelAlphabet() {
+		if _, ok := model.TokenToId(c); !ok {
+			t.Errorf("byte-level char %q missing from vocab", c)
+		}
+	}
+}
@@ -159,6 +159,27 @@ func (u *Unigram) GetVocabSize() int {
 	return len(u.vocab)
 }
 
+// GetTokenScores returns the vocabulary with the scores of the tokens, in
+// order of id.
+func (u *Unigram) GetTokenScores() []TokenScore {
+	return u.vocab
+}
+
+// GetUnkID returns the id of the unknown token, if any.
+func (u *Unigram) GetUnkID() *int {
+	return u.unkID
+}
+
+// GetByteFallback returns whether unknown characters fall back to bytes.
+func (u *Unigram) GetByteFallback() bool {
+	return u.bytesFallback
+}
+
+// GetFuseUnk returns whether consecutive unknown tokens are fused.
+func (u *Unigram) GetFuseUnk() bool {
+	return u.fuseUnk
+}
+
 // TokenToId returns the ID for the given token
 func (u *Unigram) TokenToId(token string) (int, bool) {
 	id, ok := u.tokenToIDs[token]
@@ -244,11 +265,66 @@ func (u *Unigram) Save(dir string, prefixOpt ...string) error {
 
 // Tokenize tokenizes the given sequence into multiple tokens
 func (u *Unigram) Tokenize(sequence string) ([]tokenizer.Token, error) {
+	tokens, err := u.pieces(sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.tokensToTokenizer(tokens, sequence), nil
+}
+
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider.
+func (u *Unigram) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	if u.unkID != nil && *u.unkID < len(u.vocab) {
+		roles[tokenizer.UnkRole] = u.vocab[*u.unkID].Token
+	}
+
+	return roles
+}
+
+// Clone implements tokenizer.Cloner. The clone starts with an empty cache.
+func (u *Unigram) Clone() tokenizer.Model {
+	clone := *u
+	clone.vocab = append([]TokenScore(nil), u.vocab...)
+	clone.tokenToIDs = make(map[string]int, len(u.tokenToIDs))
+	for k, v := range u.tokenToIDs {
+		clone.tokenToIDs[k] = v
+	}
+	if u.unkID != nil {
+		unkID := *u.unkID
+		clone.unkID = &unkID
+	}
+	clone.cache = Catch.New(CacheExpiredTime*time.Minute, CacheCleanTime*time.Minute)
+
+	return &clone
+}
+
+// CountTokens implements tokenizer.TokenCounter. It returns the number of
+// tokens `Tokenize` returns for the sequence, without building them.
+func (u *Unigram) CountTokens(sequence string) (int, error) {
+	tokens, err := u.pieces(sequence)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, token := range tokens {
+		// Unknown tokens are skipped if no unkID is defined
+		if _, ok := u.TokenToId(token); ok || u.unkID != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// pieces splits the sequence into string tokens, using the cache.
+func (u *Unigram) pieces(sequence string) ([]string, error) {
 	// Check cache first
 	data, ok := u.cache.Get(sequence)
 	if ok {
-		tokens := data.([]string)
-		return u.tokensToTokenizer(tokens, sequence), nil
+		return data.([]string), nil
 	}
 
 	// If byte fallback is enabled, always use it
@@ -256,7 +332,7 @@ func (u *Unigram) Tokenize(sequence string) ([]tokenizer.Token, error) {
 		tokens := u.tokenizeWithByteFallback(sequence)
 		u.cache.Set(sequence, tokens, CacheExpiredTime*time.Minute)
 
-		return u.tokensToTokenizer(tokens, sequence), nil
+		return tokens, nil
 	}
 
 	// Tokenize using the Viterbi algorithm
@@ -266,7 +342,7 @@ func (u *Unigram) Tokenize(sequence string) ([]tokenizer.Token, error) {
 	}
 	u.cache.Set(sequence, tokens, CacheExpiredTime)
 
-	return u.tokensToTokenizer(tokens, sequence), nil
+	return tokens, nil
 }
 
 // tokensToTokenizer converts string tokens to tokenizer.Token
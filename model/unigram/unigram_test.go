@@ -218,3 +218,34 @@ func TestUnigramByteFallback(t *testing.T) {
 		t.Errorf("Wrong first token value: got %q, want %q", got, want)
 	}
 }
+
+func TestCountTokens(t *testing.T) {
+	pieces := []TokenScore{
+		{Token: "<unk>", Score: 0.0},
+		{Token: "a", Score: 0.0},
+		{Token: "b", Score: 0.0},
+		{Token: "ab", Score: 2.0},
+	}
+	params := util.NewParams(map[string]interface{}{
+		"unk_id":        0,
+		"byte_fallback": false,
+	})
+	model, err := New(pieces, params)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	for _, seq := range []string{"", "ab", "aab", "abxb"} {
+		tokens, err := model.Tokenize(seq)
+		if err != nil {
+			t.Fatalf("Failed to tokenize: %v", err)
+		}
+		got, err := model.CountTokens(seq)
+		if err != nil {
+			t.Fatalf("Failed to count tokens: %v", err)
+		}
+		if got != len(tokens) {
+			t.Errorf("%q: got %d tokens, want %d", seq, got, len(tokens))
+		}
+	}
+}
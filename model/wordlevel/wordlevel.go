@@ -138,6 +138,11 @@ func (wl *WordLevel) GetVocabSize() (retVal int) {
 	return len(wl.vocab)
 }
 
+// GetUnkToken returns the unknown token.
+func (wl *WordLevel) GetUnkToken() string {
+	return wl.unkToken
+}
+
 // Tokenize transforms given input to token
 func (wl *WordLevel) Tokenize(token string) ([]tokenizer.Token, error) {
 
@@ -166,6 +171,44 @@ func (wl *WordLevel) Tokenize(token string) ([]tokenizer.Token, error) {
 	return output, nil
 }
 
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider.
+func (wl *WordLevel) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	if wl.unkToken != "" {
+		roles[tokenizer.UnkRole] = wl.unkToken
+	}
+
+	return roles
+}
+
+// Clone implements tokenizer.Cloner.
+func (wl *WordLevel) Clone() tokenizer.Model {
+	clone := &WordLevel{
+		vocab:    make(map[string]int, len(wl.vocab)),
+		vocabR:   make(map[int]string, len(wl.vocabR)),
+		unkToken: wl.unkToken,
+	}
+	for k, v := range wl.vocab {
+		clone.vocab[k] = v
+	}
+	for k, v := range wl.vocabR {
+		clone.vocabR[k] = v
+	}
+
+	return clone
+}
+
+// CountTokens implements tokenizer.TokenCounter. A word is always a single token.
+func (wl *WordLevel) CountTokens(token string) (int, error) {
+	if _, ok := wl.vocab[token]; !ok {
+		if _, unkOk := wl.vocab[wl.unkToken]; !unkOk {
+			return 0, fmt.Errorf("Missing 'unk' token in vocab.\n")
+		}
+	}
+
+	return 1, nil
+}
+
 // TokenToId returns id of a given token if existing
 func (wl *WordLevel) TokenToId(token string) (int, bool) {
 	id, ok := wl.vocab[token]
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"unicode/utf8"
 
 	"github.com/sugarme/tokenizer"
 	"github.com/sugarme/tokenizer/model"
@@ -251,6 +252,21 @@ func (wp WordPiece) GetVocabSize() (retVal int) {
 	return len(*wp.vocab)
 }
 
+// GetUnkToken returns the unknown token.
+func (wp WordPiece) GetUnkToken() string {
+	return wp.unkToken
+}
+
+// GetContinuingSubwordPrefix returns the prefix of continuing subwords.
+func (wp WordPiece) GetContinuingSubwordPrefix() string {
+	return wp.continueSubwordPrefix
+}
+
+// GetMaxInputCharsPerWord returns the maximum number of characters of a word.
+func (wp WordPiece) GetMaxInputCharsPerWord() int {
+	return wp.maxInputCharsPerWord
+}
+
 func (wp WordPiece) Tokenize(sequence string) (retVal []tokenizer.Token, err error) {
 
 	// fmt.Printf("input sequence: %v\n", sequence)
@@ -331,6 +347,82 @@ func (wp WordPiece) Tokenize(sequence string) (retVal []tokenizer.Token, err err
 	return outputTokens, nil
 }
 
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider.
+func (wp WordPiece) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	if wp.unkToken != "" {
+		roles[tokenizer.UnkRole] = wp.unkToken
+	}
+
+	return roles
+}
+
+// Clone implements tokenizer.Cloner.
+func (wp *WordPiece) Clone() tokenizer.Model {
+	clone := *wp
+	if wp.vocab != nil {
+		vocab := make(model.Vocab, len(*wp.vocab))
+		for k, v := range *wp.vocab {
+			vocab[k] = v
+		}
+		clone.vocab = &vocab
+	}
+	if wp.vocabR != nil {
+		vocabR := make(model.VocabR, len(*wp.vocabR))
+		for k, v := range *wp.vocabR {
+			vocabR[k] = v
+		}
+		clone.vocabR = &vocabR
+	}
+
+	return &clone
+}
+
+// CountTokens implements tokenizer.TokenCounter. It returns the number of
+// tokens `Tokenize` returns for the sequence, without building them.
+func (wp WordPiece) CountTokens(sequence string) (int, error) {
+	unk := func() (int, error) {
+		if _, ok := (*wp.vocab)[wp.unkToken]; !ok {
+			return 0, fmt.Errorf("WordPiece error: Missing [UNK] token. Unknown token value %q not found in the vocab\n", wp.unkToken)
+		}
+		return 1, nil
+	}
+
+	if utf8.RuneCountInString(sequence) > wp.maxInputCharsPerWord {
+		return unk()
+	}
+
+	// Lookups with `string(buf)` don't allocate
+	buf := make([]byte, 0, len(wp.continueSubwordPrefix)+len(sequence))
+	count := 0
+	start := 0
+	for start < len(sequence) {
+		end := len(sequence)
+		found := false
+		for start < end {
+			buf = buf[:0]
+			if start > 0 {
+				buf = append(buf, wp.continueSubwordPrefix...)
+			}
+			buf = append(buf, sequence[start:end]...)
+			if _, ok := (*wp.vocab)[string(buf)]; ok {
+				found = true
+				break
+			}
+			_, size := utf8.DecodeLastRuneInString(sequence[start:end])
+			end -= size
+		}
+		if !found {
+			return unk()
+		}
+
+		count++
+		start = end
+	}
+
+	return count, nil
+}
+
 func (wp WordPiece) TokenToId(token string) (retVal int, ok bool) {
 	retVal, ok = (*wp.vocab)[token]
 	return
@@ -418,7 +510,7 @@ func New(
 	if opts.Has("unk_token") {
 		unkToken = opts.Get("unk_token").(string)
 	}
-	if opts.Has("continuingSubwordPrefix") {
+	if opts.Has("continuing_subword_prefix") {
 		continuingSubwordPrefix = opts.Get("continuing_subword_prefix").(string)
 	}
 	if opts.Has("max_input_chars_per_word") {
@@ -96,6 +96,11 @@ func NewStringPattern(s string) *StringPattern {
 	return &StringPattern{s}
 }
 
+// String returns the string to match.
+func (s *StringPattern) String() string {
+	return s.string
+}
+
 func (s *StringPattern) FindMatches(inside string) []OffsetsMatch {
 	// If we try to find the matches with an empty string, just don't match anything
 	if s.string == "" {
@@ -195,6 +200,11 @@ func NewRegexpPattern(s string) *RegexpPattern {
 	}
 }
 
+// String returns the source text of the regular expression.
+func (rp *RegexpPattern) String() string {
+	return rp.re.String()
+}
+
 // FindMatches implements Pattern interface for RegexpPattern
 func (rp *RegexpPattern) FindMatches(inside string) []OffsetsMatch {
 	if len(inside) == 0 {
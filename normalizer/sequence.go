@@ -12,6 +12,20 @@ func NewSequence(norms []Normalizer) *Sequence {
 	return &Sequence{norms}
 }
 
+// Clone returns a deep copy of the sequence, cloning the normalizers that can
+// be cloned. It implements `tokenizer.Cloner[Normalizer]`.
+func (s *Sequence) Clone() Normalizer {
+	norms := make([]Normalizer, len(s.Normalizers))
+	for i, n := range s.Normalizers {
+		if c, ok := n.(interface{ Clone() Normalizer }); ok {
+			n = c.Clone()
+		}
+		norms[i] = n
+	}
+
+	return NewSequence(norms)
+}
+
 // Implement Normalizer for Sequence
 func (s *Sequence) Normalize(normalized *NormalizedString) (*NormalizedString, error) {
 	input := normalized
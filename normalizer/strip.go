@@ -12,6 +12,16 @@ func NewStrip(stripLeft, stripRight bool) *Strip {
 	}
 }
 
+// GetStripLeft returns whether leading whitespaces are stripped.
+func (s *Strip) GetStripLeft() bool {
+	return s.stripLeft
+}
+
+// GetStripRight returns whether trailing whitespaces are stripped.
+func (s *Strip) GetStripRight() bool {
+	return s.stripRight
+}
+
 // Implement Normalizer interface for Strip:
 // =========================================
 
@@ -4,9 +4,11 @@ package tokenizer
 
 import (
 	"fmt"
-	"log"
+	"unicode/utf8"
 	// "reflect"
 
+	"github.com/rivo/uniseg"
+
 	"github.com/sugarme/tokenizer/normalizer"
 )
 
@@ -22,8 +24,49 @@ type OffsetType int
 const (
 	Byte OffsetType = iota
 	Char
+	// UTF16 offsets count UTF-16 code units, as JavaScript string indexes do.
+	UTF16
+	// Grapheme offsets count grapheme clusters, i.e. user-perceived characters.
+	Grapheme
 )
 
+var offsetTypeNames = map[OffsetType]string{
+	Byte:     "byte",
+	Char:     "char",
+	UTF16:    "utf16",
+	Grapheme: "grapheme",
+}
+
+// String implements fmt.Stringer.
+func (t OffsetType) String() string {
+	if name, ok := offsetTypeNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("OffsetType(%d)", int(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t OffsetType) MarshalText() ([]byte, error) {
+	if _, ok := offsetTypeNames[t]; !ok {
+		return nil, fmt.Errorf("Invalid offsetType (%v).\n", int(t))
+	}
+
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *OffsetType) UnmarshalText(text []byte) error {
+	for typ, name := range offsetTypeNames {
+		if name == string(text) {
+			*t = typ
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid offsetType (%q).\n", text)
+}
+
 // Split contains the underlying `NormalizedString` as well as
 // its offsets in the original string. These offsets are in the
 // `original` referential. It also contains any `Token` associated
@@ -153,26 +196,11 @@ func (pt *PreTokenizedString) IntoEncoding(typeId int, wordIdx int, offsetType O
 		}
 	}
 
-	charMap := make(map[int]int, 0) // map[byteIdx]runeIdx
-
-	switch {
-	case offsetType == Char:
-		currRuneIdx := 0
-		for byteIdx, r := range pt.original {
-			n := 0
-			for i := 0; i < len([]byte(string(r))); i++ {
-				charMap[byteIdx+n] = currRuneIdx
-				n += 1
-			}
-			currRuneIdx += 1
-		}
-	case offsetType == Byte:
-		charMap = make(map[int]int, 0)
-
-	default:
+	if _, ok := offsetTypeNames[offsetType]; !ok {
 		err := fmt.Errorf("Invalid offsetType (%v).\n", offsetType)
 		return nil, err
 	}
+	offsetConverter := NewOffsetConverter(pt.original, offsetType)
 
 	var (
 		enIds               []int
@@ -187,7 +215,6 @@ func (pt *PreTokenizedString) IntoEncoding(typeId int, wordIdx int, offsetType O
 	for idx, split := range pt.splits {
 		normalized := split.normalized
 		offsets := normalized.OffsetsOriginal()
-		charMapSplit := charMap
 		var convertedOffsets []int
 		for _, tok := range split.tokens {
 			o := normalized.ConvertOffset(normalizer.NewRange(tok.Offsets[0], tok.Offsets[1], normalizer.NormalizedTarget))
@@ -197,31 +224,12 @@ func (pt *PreTokenizedString) IntoEncoding(typeId int, wordIdx int, offsetType O
 				convertedOffsets = []int{offsets[0] + o.Start(), offsets[0] + o.End()}
 			}
 
-			// Convert to char offset if relevant
-			start, ok := charMapSplit[convertedOffsets[0]]
-			if !ok {
-				start = -1
-			}
-			end, ok := charMapSplit[convertedOffsets[1]]
-			if !ok {
-				end = -1
-			}
-
-			var newConvertedOffsets []int
-			switch {
-			case start != -1 && end != -1:
-				newConvertedOffsets = []int{start, end}
-			case start != -1 && end == -1: // If we reached the end, `end` is not in the map
-				// But the one just before should be
-				last, ok := charMapSplit[convertedOffsets[1]-1]
-				if !ok {
-					log.Printf("Something wrong here. Should find from map.\n")
-					last = start + 1
+			// Convert to the requested offset unit if relevant
+			newConvertedOffsets := convertedOffsets
+			if offsetConverter != nil {
+				if o, err := offsetConverter.Convert(convertedOffsets); err == nil {
+					newConvertedOffsets = o
 				}
-				newConvertedOffsets = []int{start, last}
-
-			default:
-				newConvertedOffsets = convertedOffsets
 			}
 
 			var wordIndex int = wordIdx
@@ -250,6 +258,7 @@ func (pt *PreTokenizedString) IntoEncoding(typeId int, wordIdx int, offsetType O
 	en.TypeIds = enTypeIds
 	en.SpecialTokenMask = enSpecialTokensMask
 	en.AttentionMask = enAttentionMask
+	en.OffsetType = offsetType
 
 	return en, nil
 }
@@ -260,10 +269,7 @@ func (pt *PreTokenizedString) IntoEncoding(typeId int, wordIdx int, offsetType O
 func (pt *PreTokenizedString) GetSplits(offsetRef normalizer.IndexOn, offsetType OffsetType) []PreToken {
 	var preToks []PreToken
 
-	var offsetConverter OffsetConverter
-	if offsetType == Char {
-		offsetConverter = NewBytesToCharOffsetConverter(pt.original)
-	}
+	offsetConverter := NewOffsetConverter(pt.original, offsetType)
 
 	offset := 0
 	for _, s := range pt.splits {
@@ -312,6 +318,72 @@ type OffsetConverter interface {
 	Convert(offsets []int) ([]int, error)
 }
 
+// NewOffsetConverter returns an OffsetConverter converting byte offsets of the
+// given sequence to the given offset type. It returns nil for `Byte`.
+//
+// Offsets falling inside a unit (e.g. a byte-level token splitting a character)
+// are widened to cover the whole unit.
+func NewOffsetConverter(sequence string, offsetType OffsetType) OffsetConverter {
+	if offsetType == Byte {
+		return nil
+	}
+
+	c := &unitOffsetConverter{
+		floor: make([]int, len(sequence)+1),
+		ceil:  make([]int, len(sequence)+1),
+	}
+
+	unit := 0
+	add := func(start, end, width int) {
+		for b := start; b < end; b++ {
+			c.floor[b] = unit
+			c.ceil[b] = unit + width
+		}
+		c.ceil[start] = unit
+		unit += width
+	}
+
+	switch offsetType {
+	case Grapheme:
+		gr := uniseg.NewGraphemes(sequence)
+		for gr.Next() {
+			start, end := gr.Positions()
+			add(start, end, 1)
+		}
+	default:
+		for start := 0; start < len(sequence); {
+			r, size := utf8.DecodeRuneInString(sequence[start:])
+			width := 1
+			if offsetType == UTF16 && r >= 0x10000 {
+				width = 2 // surrogate pair
+			}
+			add(start, start+size, width)
+			start += size
+		}
+	}
+	c.floor[len(sequence)] = unit
+	c.ceil[len(sequence)] = unit
+
+	return c
+}
+
+// unitOffsetConverter converts byte offsets using precomputed tables.
+type unitOffsetConverter struct {
+	floor []int // index of the unit containing each byte
+	ceil  []int // index of the first unit starting at or after each byte
+}
+
+// Convert converts byte offsets to unit offsets.
+func (c *unitOffsetConverter) Convert(offsets []int) ([]int, error) {
+	start, end := offsets[0], offsets[1]
+	if start < 0 || end < start || end >= len(c.floor) {
+		err := fmt.Errorf("Invalid offsets %v\n", offsets)
+		return nil, err
+	}
+
+	return []int{c.floor[start], c.ceil[end]}, nil
+}
+
 type BytesToCharOffsetConverter struct {
 	b2c map[int]int // map of byteIndex to character(rune) index
 }
@@ -14,6 +14,21 @@ func NewSequence(pretokenizers []tokenizer.PreTokenizer) *Sequence {
 	return &Sequence{pretokenizers}
 }
 
+// PreTokenizers returns the pre-tokenizers of the sequence.
+func (p *Sequence) PreTokenizers() []tokenizer.PreTokenizer {
+	return p.pretokenizers
+}
+
+// Clone implements tokenizer.Cloner.
+func (p *Sequence) Clone() tokenizer.PreTokenizer {
+	pretokenizers := make([]tokenizer.PreTokenizer, len(p.pretokenizers))
+	for i, pretok := range p.pretokenizers {
+		pretokenizers[i] = tokenizer.CloneComponent(pretok)
+	}
+
+	return NewSequence(pretokenizers)
+}
+
 // Implement tokenizer.PreTokenizer for Sequence
 
 func (p *Sequence) PreTokenize(v *tokenizer.PreTokenizedString) (*tokenizer.PreTokenizedString, error) {
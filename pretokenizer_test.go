@@ -40,3 +40,44 @@ func TestBytesToCharConverter(t *testing.T) {
 		t.Errorf("want %v, got %v\n", want, got)
 	}
 }
+
+func TestOffsetConverter(t *testing.T) {
+	// "é" is 2 bytes, "😀" is 4 bytes and 2 UTF-16 code units,
+	// "👍🏽" is 2 runes (8 bytes) but a single grapheme.
+	sequence := "é 😀 👍🏽!"
+
+	tests := []struct {
+		offsetType OffsetType
+		offsets    [][]int
+		want       [][]int
+	}{
+		{Char, [][]int{{0, 2}, {3, 7}, {8, 16}, {16, 17}}, [][]int{{0, 1}, {2, 3}, {4, 6}, {6, 7}}},
+		{UTF16, [][]int{{0, 2}, {3, 7}, {8, 16}, {16, 17}}, [][]int{{0, 1}, {2, 4}, {5, 9}, {9, 10}}},
+		{Grapheme, [][]int{{0, 2}, {3, 7}, {8, 16}, {16, 17}}, [][]int{{0, 1}, {2, 3}, {4, 5}, {5, 6}}},
+		// Offsets inside a unit are widened to the whole unit
+		{Char, [][]int{{0, 1}, {4, 5}}, [][]int{{0, 1}, {2, 3}}},
+		{Grapheme, [][]int{{8, 12}}, [][]int{{4, 5}}},
+	}
+
+	for _, tt := range tests {
+		converter := NewOffsetConverter(sequence, tt.offsetType)
+		var got [][]int
+		for _, o := range tt.offsets {
+			c, err := converter.Convert(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, c)
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("%v: want %v, got %v", tt.offsetType, tt.want, got)
+		}
+	}
+
+	if NewOffsetConverter(sequence, Byte) != nil {
+		t.Errorf("want no converter for byte offsets")
+	}
+	if _, err := NewOffsetConverter(sequence, Char).Convert([]int{0, 18}); err == nil {
+		t.Errorf("want error for out of range offsets")
+	}
+}
@@ -29,6 +29,9 @@ func CreateDecoder(config map[string]interface{}) (tokenizer.Decoder, error) {
 
 	params := util.NewParams(config)
 	typ := params.Get("type").(string)
+	if create, ok := decoders.constructor(typ); ok {
+		return create(config)
+	}
 
 	switch typ {
 	case "BPE":
@@ -82,10 +85,12 @@ func createMetaspaceDecoder(params *util.Params) (*pretokenizer.Metaspace, error
 		return nil, nil
 	}
 
-	replacement := params.Get("replacement", "").(string)
-	addPrefixSpace := params.Get("add_prefix_space", false).(bool)
+	m, err := createMetaspacePreTokenizer(params)
+	if err != nil {
+		return nil, err
+	}
 
-	return pretokenizer.NewMetaspace(replacement, addPrefixSpace), nil
+	return m.(*pretokenizer.Metaspace), nil
 }
 
 func createCTCDecoder(params *util.Params) (*decoder.CTC, error) {
@@ -94,7 +99,12 @@ func createCTCDecoder(params *util.Params) (*decoder.CTC, error) {
 	}
 
 	padToken := params.Get("pad_token").(string)
-	wordDelimiter := params.Get("word_delimiter").(string)
+	var wordDelimiter string
+	if params.Has("word_delimiter_token") {
+		wordDelimiter = params.Get("word_delimiter_token").(string)
+	} else {
+		wordDelimiter = params.Get("word_delimiter").(string)
+	}
 	cleanup := params.Get("cleanup").(bool)
 
 	return decoder.NewCTC(padToken, wordDelimiter, cleanup), nil
@@ -167,9 +177,9 @@ func createReplaceDecoder(params *util.Params) (*normalizer.Replace, error) {
 		pattern = pparams.Get("String").(string)
 		patternType = normalizer.String
 
-	case params.Has("Regex"):
+	case pparams.Has("Regex"):
 		pattern = pparams.Get("Regex").(string)
-		patternType = normalizer.String
+		patternType = normalizer.Regex
 	}
 
 	content := params.Get("content").(string)
@@ -193,3 +203,47 @@ func createStripDecoder(params *util.Params) (*decoder.Strip, error) {
 
 	return decoder.NewStrip(content, start, stop), nil
 }
+
+// serializeDecoder returns the json config of a built-in decoder.
+func serializeDecoder(d tokenizer.Decoder) (map[string]interface{}, bool, error) {
+	switch d := d.(type) {
+	case *decoder.BpeDecoder:
+		return map[string]interface{}{"type": "BPE", "suffix": d.GetSuffix()}, true, nil
+	case *pretokenizer.ByteLevel:
+		return map[string]interface{}{
+			"type":             "ByteLevel",
+			"add_prefix_space": d.AddPrefixSpace,
+			"trim_offsets":     d.TrimOffsets,
+		}, true, nil
+	case *decoder.WordPieceDecoder:
+		return map[string]interface{}{
+			"type":    "WordPiece",
+			"prefix":  d.GetPrefix(),
+			"cleanup": d.GetCleanup(),
+		}, true, nil
+	case *pretokenizer.Metaspace:
+		return serializeMetaspace(d)
+	case *decoder.CTC:
+		return map[string]interface{}{
+			"type":                 "CTC",
+			"pad_token":            d.PadToken,
+			"word_delimiter_token": d.WordDelimiterToken,
+			"cleanup":              d.Cleanup,
+		}, true, nil
+	case *normalizer.Replace:
+		return serializeReplace(d)
+	case *decoder.Fuse:
+		return map[string]interface{}{"type": "Fuse"}, true, nil
+	case *decoder.Strip:
+		return map[string]interface{}{
+			"type":    "Strip",
+			"content": d.Content,
+			"start":   d.Start,
+			"stop":    d.Stop,
+		}, true, nil
+	case *decoder.ByteFallback:
+		return map[string]interface{}{"type": "ByteFallback"}, true, nil
+	}
+
+	return nil, false, nil
+}
@@ -3,6 +3,7 @@ package pretrained
 import (
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/sugarme/tokenizer"
 	"github.com/sugarme/tokenizer/model"
@@ -49,6 +50,10 @@ func CreateModel(config *tokenizer.Config) (tokenizer.Model, error) {
 		}
 	}
 
+	if create, ok := models.constructor(typ); ok {
+		return create(config.Model)
+	}
+
 	switch typ {
 	case "BPE":
 		return createBPE(params)
@@ -128,7 +133,7 @@ func createWordPiece(params *util.Params) (tokenizer.Model, error) {
 	}
 	if params.Has("continuing_subword_prefix") {
 		v := params.Get("continuing_subword_prefix").(string)
-		opts.Get("continuing_subword_prefix", v)
+		opts.Set("continuing_subword_prefix", v)
 	}
 
 	if params.Has("max_input_chars_per_word") {
@@ -237,3 +242,80 @@ func castMerge(input []interface{}) ([]string, error) {
 
 	return out, nil
 }
+
+// serializeModel returns the json config of a built-in model.
+func serializeModel(m tokenizer.Model) (map[string]interface{}, bool, error) {
+	switch m := m.(type) {
+	case *bpe.BPE:
+		return serializeBPE(m)
+	case wordpiece.WordPiece:
+		return serializeWordPiece(m)
+	case *wordpiece.WordPiece:
+		return serializeWordPiece(*m)
+	case *wordlevel.WordLevel:
+		return map[string]interface{}{
+			"type":      "WordLevel",
+			"vocab":     m.GetVocab(),
+			"unk_token": m.GetUnkToken(),
+		}, true, nil
+	case *unigram.Unigram:
+		vocab := make([]interface{}, len(m.GetTokenScores()))
+		for i, ts := range m.GetTokenScores() {
+			vocab[i] = []interface{}{ts.Token, ts.Score}
+		}
+		return map[string]interface{}{
+			"type":          "Unigram",
+			"unk_id":        m.GetUnkID(),
+			"vocab":         vocab,
+			"byte_fallback": m.GetByteFallback(),
+			"fuse_unk":      m.GetFuseUnk(),
+		}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func serializeBPE(m *bpe.BPE) (map[string]interface{}, bool, error) {
+	type rankedMerge struct {
+		merge string
+		rank  int
+	}
+	var ranked []rankedMerge
+	if m.Merges != nil {
+		for pair, val := range *m.Merges {
+			c1, ok1 := m.IdToToken(pair.C1)
+			c2, ok2 := m.IdToToken(pair.C2)
+			if !ok1 || !ok2 {
+				return nil, true, fmt.Errorf("Could not serialize BPE merge %v: unknown token id.\n", pair)
+			}
+			ranked = append(ranked, rankedMerge{c1 + " " + c2, val.Rank})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].rank < ranked[j].rank
+	})
+	merges := make([]interface{}, len(ranked))
+	for i, r := range ranked {
+		merges[i] = r.merge
+	}
+
+	return map[string]interface{}{
+		"type":                      "BPE",
+		"dropout":                   m.Dropout,
+		"unk_token":                 m.UnkToken,
+		"continuing_subword_prefix": m.ContinuingSubwordPrefix,
+		"end_of_word_suffix":        m.EndOfWordSuffix,
+		"vocab":                     m.GetVocab(),
+		"merges":                    merges,
+	}, true, nil
+}
+
+func serializeWordPiece(m wordpiece.WordPiece) (map[string]interface{}, bool, error) {
+	return map[string]interface{}{
+		"type":                      "WordPiece",
+		"unk_token":                 m.GetUnkToken(),
+		"continuing_subword_prefix": m.GetContinuingSubwordPrefix(),
+		"max_input_chars_per_word":  m.GetMaxInputCharsPerWord(),
+		"vocab":                     m.GetVocab(),
+	}, true, nil
+}
@@ -17,6 +17,7 @@ package pretrained
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/sugarme/tokenizer/normalizer"
 	"github.com/sugarme/tokenizer/spm"
@@ -33,6 +34,9 @@ func CreateNormalizer(config map[string]interface{}) (normalizer.Normalizer, err
 	params := util.NewParams(config)
 
 	typ := params.Get("type").(string)
+	if create, ok := normalizers.constructor(typ); ok {
+		return create(config)
+	}
 
 	switch typ {
 	case "BertNormalizer":
@@ -110,9 +114,9 @@ func createReplaceNormalizer(params *util.Params) (normalizer.Normalizer, error)
 		pattern = pparams.Get("String").(string)
 		patternType = normalizer.String
 
-	case params.Has("Regex"):
+	case pparams.Has("Regex"):
 		pattern = pparams.Get("Regex").(string)
-		patternType = normalizer.String
+		patternType = normalizer.Regex
 	}
 
 	content := params.Get("content").(string)
@@ -195,3 +199,76 @@ func createSequenceNormalizer(params *util.Params) (normalizer.Normalizer, error
 
 	return seq, nil
 }
+
+// serializeNormalizer returns the json config of a built-in normalizer.
+func serializeNormalizer(n normalizer.Normalizer) (map[string]interface{}, bool, error) {
+	switch n := n.(type) {
+	case *normalizer.BertNormalizer:
+		return map[string]interface{}{
+			"type":                 "BertNormalizer",
+			"clean_text":           n.CleanText,
+			"handle_chinese_chars": n.HandleChineseChars,
+			"strip_accents":        n.StripAccents,
+			"lowercase":            n.Lowercase,
+		}, true, nil
+	case *normalizer.Strip:
+		return map[string]interface{}{
+			"type":        "Strip",
+			"strip_left":  n.GetStripLeft(),
+			"strip_right": n.GetStripRight(),
+		}, true, nil
+	case *normalizer.StripAccents:
+		return map[string]interface{}{"type": "StripAccents"}, true, nil
+	case *normalizer.NFC:
+		return map[string]interface{}{"type": "NFC"}, true, nil
+	case *normalizer.NFD:
+		return map[string]interface{}{"type": "NFD"}, true, nil
+	case *normalizer.NFKC:
+		return map[string]interface{}{"type": "NFKC"}, true, nil
+	case *normalizer.NFKD:
+		return map[string]interface{}{"type": "NFKD"}, true, nil
+	case *normalizer.DefaultNormalizer:
+		// Only the normalizer of `normalizer.Lowercase()` has a json config.
+		if !reflect.DeepEqual(n, normalizer.Lowercase()) {
+			return nil, false, nil
+		}
+		return map[string]interface{}{"type": "Lowercase"}, true, nil
+	case *normalizer.Precompiled:
+		return map[string]interface{}{
+			"type":                 "Precompiled",
+			"precompiled_charsmap": spm.AsBase64(n.PrecompiledCharsmap),
+		}, true, nil
+	case *normalizer.Replace:
+		return serializeReplace(n)
+	case *normalizer.Prepend:
+		return map[string]interface{}{"type": "Prepend", "prepend": n.Prepend}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// serializeReplace returns the json config of a Replace normalizer or decoder.
+func serializeReplace(r *normalizer.Replace) (map[string]interface{}, bool, error) {
+	pattern, err := serializePattern(r.Pattern)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return map[string]interface{}{
+		"type":    "Replace",
+		"pattern": pattern,
+		"content": r.Content,
+	}, true, nil
+}
+
+// serializePattern returns the json config of a string or regexp pattern.
+func serializePattern(p normalizer.Pattern) (map[string]interface{}, error) {
+	switch p := p.(type) {
+	case *normalizer.StringPattern:
+		return map[string]interface{}{"String": p.String()}, nil
+	case *normalizer.RegexpPattern:
+		return map[string]interface{}{"Regex": p.String()}, nil
+	}
+
+	return nil, fmt.Errorf("Could not serialize pattern %T.\n", p)
+}
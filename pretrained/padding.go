@@ -1,6 +1,7 @@
 package pretrained
 
 import (
+	"encoding/json"
 	"reflect"
 
 	"github.com/sugarme/tokenizer"
@@ -118,11 +119,38 @@ func CreatePaddingParams(config map[string]interface{}) (*tokenizer.PaddingParam
 		token = "[PAD]" // Default pad token
 	}
 
+	var multiple int
+	if multipleVal := params.Get("pad_to_multiple_of"); multipleVal != nil {
+		if fVal, ok := multipleVal.(float64); ok {
+			multiple = int(fVal)
+		}
+	}
+
 	return &tokenizer.PaddingParams{
-		Strategy:  *strategy,
-		Direction: direction,
-		PadId:     id,
-		PadTypeId: typeId,
-		PadToken:  token,
+		Strategy:        *strategy,
+		Direction:       direction,
+		PadId:           id,
+		PadTypeId:       typeId,
+		PadToken:        token,
+		PadToMultipleOf: multiple,
 	}, nil
 }
+
+// serializePaddingParams returns the json config of padding params, in the
+// format of `PaddingParams.MarshalJSON`.
+func serializePaddingParams(p *tokenizer.PaddingParams) (map[string]interface{}, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,40 @@
+package pretrained
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+)
+
+func TestCreatePaddingParams_RoundTrip(t *testing.T) {
+	want := &tokenizer.PaddingParams{
+		Strategy:        *tokenizer.NewPaddingStrategy(tokenizer.WithFixed(128)),
+		Direction:       tokenizer.Left,
+		PadId:           1,
+		PadTypeId:       2,
+		PadToken:        "<pad>",
+		PadToMultipleOf: 8,
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := CreatePaddingParams(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %+v\n", want)
+		t.Errorf("Got: %+v\n", got)
+	}
+}
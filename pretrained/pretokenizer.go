@@ -30,13 +30,16 @@ func CreatePreTokenizer(config map[string]interface{}) (tokenizer.PreTokenizer,
 
 	params := util.NewParams(config)
 	typ := params.Get("type").(string)
+	if create, ok := preTokenizers.constructor(typ); ok {
+		return create(config)
+	}
 
 	switch typ {
 	case "BertPreTokenizer":
 		return pretokenizer.NewBertPreTokenizer(), nil
 	case "ByteLevel":
 		return createByteLevelPreTokenizer(params)
-	case "Delimiter":
+	case "Delimiter", "CharDelimiterSplit":
 		return createDelimiterPreTokenizer(params)
 	case "Metaspace":
 		return createMetaspacePreTokenizer(params)
@@ -188,7 +191,7 @@ func createSplitPreTokenizer(params *util.Params) (tokenizer.PreTokenizer, error
 	if v, ok := patternMap["Regex"]; ok {
 		pattern = normalizer.NewRegexpPattern(v.(string))
 	} else if v, ok := patternMap["String"]; ok {
-		pattern = normalizer.NewRegexpPattern(v.(string))
+		pattern = normalizer.NewStringPattern(v.(string))
 	} else {
 		err := fmt.Errorf("Unsupported pattern: %#v\n", patternMap)
 		return nil, err
@@ -231,3 +234,97 @@ func createSequencePreTokenizer(params *util.Params) (tokenizer.PreTokenizer, er
 
 	return out, nil
 }
+
+// splitBehaviors are the json names of the split delimiter behaviors.
+var splitBehaviors = []string{
+	normalizer.RemovedBehavior:            "Removed",
+	normalizer.IsolatedBehavior:           "Isolated",
+	normalizer.MergedWithPreviousBehavior: "MergedWithPrevious",
+	normalizer.MergedWithNextBehavior:     "MergedWithNext",
+	normalizer.ContiguousBehavior:         "Contiguous",
+}
+
+func splitBehaviorName(b normalizer.SplitDelimiterBehavior) (string, error) {
+	if b < 0 || int(b) >= len(splitBehaviors) {
+		return "", fmt.Errorf("Unsupported behavior: %#v\n", b)
+	}
+
+	return splitBehaviors[b], nil
+}
+
+// prependSchemes are the json names of the Metaspace prepend schemes.
+var prependSchemes = []string{
+	pretokenizer.Never:  "never",
+	pretokenizer.First:  "first",
+	pretokenizer.Always: "always",
+}
+
+// serializeMetaspace returns the json config of a Metaspace pre-tokenizer or
+// decoder.
+func serializeMetaspace(m *pretokenizer.Metaspace) (map[string]interface{}, bool, error) {
+	if m.PrependScheme < 0 || int(m.PrependScheme) >= len(prependSchemes) {
+		return nil, true, fmt.Errorf("unknown prepend_scheme: %v", m.PrependScheme)
+	}
+
+	return map[string]interface{}{
+		"type":             "Metaspace",
+		"replacement":      m.Replacement,
+		"prepend_scheme":   prependSchemes[m.PrependScheme],
+		"add_prefix_space": m.AddPrefixSpace,
+	}, true, nil
+}
+
+// serializePreTokenizer returns the json config of a built-in pre-tokenizer.
+func serializePreTokenizer(p tokenizer.PreTokenizer) (map[string]interface{}, bool, error) {
+	switch p := p.(type) {
+	case *pretokenizer.BertPreTokenizer:
+		return map[string]interface{}{"type": "BertPreTokenizer"}, true, nil
+	case *pretokenizer.ByteLevel:
+		return map[string]interface{}{
+			"type":             "ByteLevel",
+			"add_prefix_space": p.AddPrefixSpace,
+			"trim_offsets":     p.TrimOffsets,
+		}, true, nil
+	case *pretokenizer.CharDelimiterSplit:
+		return map[string]interface{}{
+			"type":      "CharDelimiterSplit",
+			"delimiter": string(p.Delimiter),
+		}, true, nil
+	case *pretokenizer.Metaspace:
+		return serializeMetaspace(p)
+	case *pretokenizer.Whitespace:
+		return map[string]interface{}{"type": "Whitespace"}, true, nil
+	case *pretokenizer.WhitespaceSplit:
+		return map[string]interface{}{"type": "WhitespaceSplit"}, true, nil
+	case *pretokenizer.Punctuation:
+		behavior, err := splitBehaviorName(p.Behavior)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{"type": "Punctuation", "behavior": behavior}, true, nil
+	case *pretokenizer.Digits:
+		return map[string]interface{}{
+			"type":              "Digits",
+			"individual_digits": p.IndividualDigits,
+		}, true, nil
+	case *pretokenizer.UnicodeScript:
+		return map[string]interface{}{"type": "UnicodeScripts"}, true, nil
+	case *pretokenizer.Split:
+		pattern, err := serializePattern(p.Pattern)
+		if err != nil {
+			return nil, true, err
+		}
+		behavior, err := splitBehaviorName(p.Behavior)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{
+			"type":     "Split",
+			"pattern":  pattern,
+			"behavior": behavior,
+			"invert":   p.Invert,
+		}, true, nil
+	}
+
+	return nil, false, nil
+}
@@ -24,6 +24,9 @@ func CreatePostProcessor(config map[string]interface{}) (tokenizer.PostProcessor
 	params := util.NewParams(config)
 
 	typ := params.Get("type").(string)
+	if create, ok := postProcessors.constructor(typ); ok {
+		return create(config)
+	}
 
 	switch typ {
 	case "RobertaProcessing": // Bart
@@ -226,3 +229,83 @@ func createSequence(params *util.Params) (tokenizer.PostProcessor, error) {
 
 	return seqProcessor, nil
 }
+
+// serializeTemplate returns the json config of the pieces of a template.
+func serializeTemplate(t processor.Template) ([]interface{}, error) {
+	pieces := make([]interface{}, 0, len(t))
+	for _, piece := range t {
+		switch p := piece.(type) {
+		case *processor.SequencePiece:
+			id := string(rune('A' + p.Id))
+			pieces = append(pieces, map[string]interface{}{
+				"Sequence": map[string]interface{}{"id": id, "type_id": p.TypeId},
+			})
+		case *processor.SpecialTokenPiece:
+			pieces = append(pieces, map[string]interface{}{
+				"SpecialToken": map[string]interface{}{"id": p.Id, "type_id": p.TypeId},
+			})
+		default:
+			return nil, fmt.Errorf("Could not serialize template piece %T.\n", piece)
+		}
+	}
+
+	return pieces, nil
+}
+
+// serializePostProcessor returns the json config of a built-in post-processor.
+func serializePostProcessor(p tokenizer.PostProcessor) (map[string]interface{}, bool, error) {
+	postToken := func(tok processor.PostToken) []interface{} {
+		return []interface{}{tok.Value, tok.Id}
+	}
+
+	switch p := p.(type) {
+	case *processor.RobertaProcessing:
+		return map[string]interface{}{
+			"type":             "RobertaProcessing",
+			"sep":              postToken(p.GetSep()),
+			"cls":              postToken(p.GetCls()),
+			"trim_offsets":     p.GetTrimOffsets(),
+			"add_prefix_space": p.GetAddPrefixSpace(),
+		}, true, nil
+	case *processor.BertProcessing:
+		return map[string]interface{}{
+			"type": "BertProcessing",
+			"sep":  postToken(p.GetSep()),
+			"cls":  postToken(p.GetCls()),
+		}, true, nil
+	case *processor.ByteLevelProcessing:
+		pretok := p.GetPreTokenizer()
+		return map[string]interface{}{
+			"type":             "ByteLevel",
+			"add_prefix_space": pretok.AddPrefixSpace,
+			"trim_offsets":     pretok.TrimOffsets,
+		}, true, nil
+	case *processor.TemplateProcessing:
+		single, err := serializeTemplate(p.Single)
+		if err != nil {
+			return nil, true, err
+		}
+		pair, err := serializeTemplate(p.Pair)
+		if err != nil {
+			return nil, true, err
+		}
+		specialTokens := make(map[string]interface{})
+		if p.SpecialTokens != nil {
+			for id, tok := range p.SpecialTokens.TokenMap {
+				specialTokens[id] = map[string]interface{}{
+					"id":     tok.Id,
+					"ids":    tok.Ids,
+					"tokens": tok.Tokens,
+				}
+			}
+		}
+		return map[string]interface{}{
+			"type":           "TemplateProcessing",
+			"single":         single,
+			"pair":           pair,
+			"special_tokens": specialTokens,
+		}, true, nil
+	}
+
+	return nil, false, nil
+}
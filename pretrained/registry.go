@@ -0,0 +1,287 @@
+package pretrained
+
+// This file provides a registry of custom components so that applications can
+// load (`Create*`) and save (`Serialize*`) their own normalizers,
+// pre-tokenizers, models, post-processors and decoders under new `type` names
+// of `tokenizer.json`, including inside `Sequence` containers.
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/decoder"
+	"github.com/sugarme/tokenizer/normalizer"
+	"github.com/sugarme/tokenizer/pretokenizer"
+	"github.com/sugarme/tokenizer/processor"
+)
+
+// Constructor creates a component from its json config, including its "type".
+type Constructor[T any] func(config map[string]interface{}) (T, error)
+
+// Serializer returns the json config of a component. It returns false if the
+// component is not one of the components it serializes. The "type" of the
+// registration is added to the config if missing.
+type Serializer[T any] func(component T) (map[string]interface{}, bool, error)
+
+type registration[T any] struct {
+	typ       string
+	create    Constructor[T]
+	serialize Serializer[T]
+}
+
+// registry holds the registered components of a kind.
+type registry[T any] struct {
+	mu    sync.RWMutex
+	kind  string
+	types map[string]int
+	regs  []registration[T]
+}
+
+func newRegistry[T any](kind string) *registry[T] {
+	return &registry[T]{kind: kind, types: make(map[string]int)}
+}
+
+func (r *registry[T]) register(typ string, create Constructor[T], serialize Serializer[T]) {
+	if typ == "" || create == nil {
+		panic(fmt.Sprintf("pretrained: Register%s() needs a type name and a constructor", r.kind))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Copy on write: serialize iterates over the slice without the lock.
+	regs := append([]registration[T]{}, r.regs...)
+	reg := registration[T]{typ: typ, create: create, serialize: serialize}
+	if i, ok := r.types[typ]; ok {
+		regs[i] = reg
+	} else {
+		r.types[typ] = len(regs)
+		regs = append(regs, reg)
+	}
+	r.regs = regs
+}
+
+func (r *registry[T]) constructor(typ string) (Constructor[T], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.types[typ]
+	if !ok {
+		return nil, false
+	}
+
+	return r.regs[i].create, true
+}
+
+// serialize tries the registered serializers in order of registration.
+func (r *registry[T]) serialize(component T) (map[string]interface{}, bool, error) {
+	r.mu.RLock()
+	regs := r.regs
+	r.mu.RUnlock()
+
+	for _, reg := range regs {
+		if reg.serialize == nil {
+			continue
+		}
+		config, ok, err := reg.serialize(component)
+		if err != nil {
+			return nil, true, err
+		}
+		if !ok {
+			continue
+		}
+		if config == nil {
+			config = make(map[string]interface{})
+		}
+		if _, ok := config["type"]; !ok {
+			config["type"] = reg.typ
+		}
+		return config, true, nil
+	}
+
+	return nil, false, nil
+}
+
+var (
+	normalizers    = newRegistry[normalizer.Normalizer]("Normalizer")
+	preTokenizers  = newRegistry[tokenizer.PreTokenizer]("PreTokenizer")
+	models         = newRegistry[tokenizer.Model]("Model")
+	postProcessors = newRegistry[tokenizer.PostProcessor]("PostProcessor")
+	decoders       = newRegistry[tokenizer.Decoder]("Decoder")
+)
+
+// RegisterNormalizer registers the constructor and the (optional) serializer
+// of the normalizers of the given type. Registered types take precedence over
+// the built-in ones, and registering a type again replaces it.
+//
+// Example:
+//
+//	pretrained.RegisterNormalizer("Uppercase",
+//		func(config map[string]interface{}) (normalizer.Normalizer, error) {
+//			return NewUppercase(), nil
+//		},
+//		func(n normalizer.Normalizer) (map[string]interface{}, bool, error) {
+//			_, ok := n.(*Uppercase)
+//			return nil, ok, nil
+//		})
+func RegisterNormalizer(typ string, create Constructor[normalizer.Normalizer], serialize Serializer[normalizer.Normalizer]) {
+	normalizers.register(typ, create, serialize)
+}
+
+// RegisterPreTokenizer registers the constructor and the (optional) serializer
+// of the pre-tokenizers of the given type.
+func RegisterPreTokenizer(typ string, create Constructor[tokenizer.PreTokenizer], serialize Serializer[tokenizer.PreTokenizer]) {
+	preTokenizers.register(typ, create, serialize)
+}
+
+// RegisterModel registers the constructor and the (optional) serializer of the
+// models of the given type. The constructor receives the "model" config.
+func RegisterModel(typ string, create Constructor[tokenizer.Model], serialize Serializer[tokenizer.Model]) {
+	models.register(typ, create, serialize)
+}
+
+// RegisterPostProcessor registers the constructor and the (optional)
+// serializer of the post-processors of the given type.
+func RegisterPostProcessor(typ string, create Constructor[tokenizer.PostProcessor], serialize Serializer[tokenizer.PostProcessor]) {
+	postProcessors.register(typ, create, serialize)
+}
+
+// RegisterDecoder registers the constructor and the (optional) serializer of
+// the decoders of the given type.
+func RegisterDecoder(typ string, create Constructor[tokenizer.Decoder], serialize Serializer[tokenizer.Decoder]) {
+	decoders.register(typ, create, serialize)
+}
+
+// SerializeNormalizer returns the json config of a normalizer: registered
+// normalizers, built-in ones and `Sequence` of them. Registered serializers
+// take precedence over the built-in ones.
+func SerializeNormalizer(n normalizer.Normalizer) (map[string]interface{}, error) {
+	if n == nil {
+		return nil, nil
+	}
+	if config, ok, err := normalizers.serialize(n); ok {
+		return config, err
+	}
+
+	if seq, ok := n.(*normalizer.Sequence); ok {
+		var configs []interface{}
+		for _, n := range seq.Normalizers {
+			config, err := SerializeNormalizer(n)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+		return map[string]interface{}{"type": "Sequence", "normalizers": configs}, nil
+	}
+
+	if config, ok, err := serializeNormalizer(n); ok {
+		return config, err
+	}
+
+	return nil, fmt.Errorf("Could not serialize Normalizer %T: no serializer.\n", n)
+}
+
+// SerializePreTokenizer returns the json config of a pre-tokenizer: registered
+// pre-tokenizers, built-in ones and `Sequence` of them.
+func SerializePreTokenizer(p tokenizer.PreTokenizer) (map[string]interface{}, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if config, ok, err := preTokenizers.serialize(p); ok {
+		return config, err
+	}
+
+	if seq, ok := p.(*pretokenizer.Sequence); ok {
+		var configs []interface{}
+		for _, p := range seq.PreTokenizers() {
+			config, err := SerializePreTokenizer(p)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+		return map[string]interface{}{"type": "Sequence", "pretokenizers": configs}, nil
+	}
+
+	if config, ok, err := serializePreTokenizer(p); ok {
+		return config, err
+	}
+
+	return nil, fmt.Errorf("Could not serialize PreTokenizer %T: no serializer.\n", p)
+}
+
+// SerializeModel returns the json config of a registered or built-in model.
+func SerializeModel(m tokenizer.Model) (map[string]interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	if config, ok, err := models.serialize(m); ok {
+		return config, err
+	}
+
+	if config, ok, err := serializeModel(m); ok {
+		return config, err
+	}
+
+	return nil, fmt.Errorf("Could not serialize Model %T: no serializer.\n", m)
+}
+
+// SerializePostProcessor returns the json config of a post-processor:
+// registered post-processors, built-in ones and `Sequence` of them.
+func SerializePostProcessor(p tokenizer.PostProcessor) (map[string]interface{}, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if config, ok, err := postProcessors.serialize(p); ok {
+		return config, err
+	}
+
+	if seq, ok := p.(*processor.Sequence); ok {
+		var configs []interface{}
+		for _, p := range seq.Processors() {
+			config, err := SerializePostProcessor(p)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+		return map[string]interface{}{"type": "Sequence", "processors": configs}, nil
+	}
+
+	if config, ok, err := serializePostProcessor(p); ok {
+		return config, err
+	}
+
+	return nil, fmt.Errorf("Could not serialize PostProcessor %T: no serializer.\n", p)
+}
+
+// SerializeDecoder returns the json config of a decoder: registered decoders,
+// built-in ones and `Sequence` of them.
+func SerializeDecoder(d tokenizer.Decoder) (map[string]interface{}, error) {
+	if d == nil {
+		return nil, nil
+	}
+	if config, ok, err := decoders.serialize(d); ok {
+		return config, err
+	}
+
+	if seq, ok := d.(*decoder.Sequence); ok {
+		var configs []interface{}
+		for _, d := range seq.Decoders() {
+			config, err := SerializeDecoder(d)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+		return map[string]interface{}{"type": "Sequence", "decoders": configs}, nil
+	}
+
+	if config, ok, err := serializeDecoder(d); ok {
+		return config, err
+	}
+
+	return nil, fmt.Errorf("Could not serialize Decoder %T: no serializer.\n", d)
+}
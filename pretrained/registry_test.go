@@ -0,0 +1,282 @@
+package pretrained
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/normalizer"
+	"github.com/sugarme/tokenizer/pretokenizer"
+)
+
+type uppercase struct{}
+
+func (uppercase) Normalize(n *normalizer.NormalizedString) (*normalizer.NormalizedString, error) {
+	return n.Uppercase(), nil
+}
+
+type delimiterSplit struct {
+	*pretokenizer.Split
+	delimiter string
+}
+
+func newDelimiterSplit(delimiter string) *delimiterSplit {
+	split := pretokenizer.NewSplit(normalizer.NewStringPattern(delimiter), normalizer.RemovedBehavior, false)
+	return &delimiterSplit{split, delimiter}
+}
+
+func init() {
+	RegisterNormalizer("Uppercase",
+		func(config map[string]interface{}) (normalizer.Normalizer, error) {
+			return uppercase{}, nil
+		},
+		func(n normalizer.Normalizer) (map[string]interface{}, bool, error) {
+			_, ok := n.(uppercase)
+			return nil, ok, nil
+		})
+
+	RegisterPreTokenizer("DelimiterSplit",
+		func(config map[string]interface{}) (tokenizer.PreTokenizer, error) {
+			return newDelimiterSplit(config["delimiter"].(string)), nil
+		},
+		func(p tokenizer.PreTokenizer) (map[string]interface{}, bool, error) {
+			s, ok := p.(*delimiterSplit)
+			if !ok {
+				return nil, false, nil
+			}
+			return map[string]interface{}{"delimiter": s.delimiter}, true, nil
+		})
+}
+
+const customTokenizerJson = `{
+  "normalizer": {"type": "Uppercase"},
+  "pre_tokenizer": {
+    "type": "Sequence",
+    "pretokenizers": [
+      {"type": "DelimiterSplit", "delimiter": "-"},
+      {"type": "DelimiterSplit", "delimiter": "_"}
+    ]
+  },
+  "model": {
+    "type": "WordLevel",
+    "vocab": {"[UNK]": 0, "AB": 1, "CD": 2, "EF": 3},
+    "unk_token": "[UNK]"
+  }
+}`
+
+func TestRegistry(t *testing.T) {
+	tk, err := FromReader(strings.NewReader(customTokenizerJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	en, err := tk.EncodeSingle("ab-cd_ef", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"AB", "CD", "EF"}; !reflect.DeepEqual(en.Tokens, want) {
+		t.Errorf("want %q, got %q", want, en.Tokens)
+	}
+
+	// Saving
+	got, err := SerializeNormalizer(tk.GetNormalizer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"type": "Uppercase"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	got, err = SerializePreTokenizer(tk.GetPreTokenizer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"type": "Sequence",
+		"pretokenizers": []interface{}{
+			map[string]interface{}{"type": "DelimiterSplit", "delimiter": "-"},
+			map[string]interface{}{"type": "DelimiterSplit", "delimiter": "_"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// Round trip of a sequence mixing the custom normalizer
+	seq := normalizer.NewSequence([]normalizer.Normalizer{uppercase{}})
+	config, err := SerializeNormalizer(seq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := CreateNormalizer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, seq) {
+		t.Errorf("want %#v, got %#v", seq, n)
+	}
+}
+
+// roundTrip serializes the config to json and back, as saving and loading do.
+func roundTrip(t *testing.T, config map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func TestRegistry_MixedSequence(t *testing.T) {
+	norm := normalizer.NewSequence([]normalizer.Normalizer{normalizer.NewNFD(), uppercase{}})
+	config, err := SerializeNormalizer(norm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := CreateNormalizer(roundTrip(t, config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, norm) {
+		t.Errorf("want %#v, got %#v", norm, n)
+	}
+
+	pretok := pretokenizer.NewSequence([]tokenizer.PreTokenizer{pretokenizer.NewBertPreTokenizer(), newDelimiterSplit("-")})
+	config, err = SerializePreTokenizer(pretok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := CreatePreTokenizer(roundTrip(t, config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, pretok) {
+		t.Errorf("want %#v, got %#v", pretok, p)
+	}
+}
+
+const builtinTokenizerJson = `{
+  "normalizer": {
+    "type": "Sequence",
+    "normalizers": [
+      {"type": "NFD"},
+      {"type": "StripAccents"},
+      {"type": "Replace", "pattern": {"Regex": "\\s+"}, "content": " "},
+      {"type": "BertNormalizer", "clean_text": true, "handle_chinese_chars": true, "strip_accents": false, "lowercase": true}
+    ]
+  },
+  "pre_tokenizer": {
+    "type": "Sequence",
+    "pretokenizers": [
+      {"type": "WhitespaceSplit"},
+      {"type": "Split", "pattern": {"String": "."}, "behavior": "Isolated", "invert": false},
+      {"type": "Punctuation", "behavior": "Isolated"},
+      {"type": "Digits", "individual_digits": true}
+    ]
+  },
+  "post_processor": {
+    "type": "TemplateProcessing",
+    "single": [
+      {"SpecialToken": {"id": "[CLS]", "type_id": 0}},
+      {"Sequence": {"id": "A", "type_id": 0}},
+      {"SpecialToken": {"id": "[SEP]", "type_id": 0}}
+    ],
+    "pair": [
+      {"SpecialToken": {"id": "[CLS]", "type_id": 0}},
+      {"Sequence": {"id": "A", "type_id": 0}},
+      {"SpecialToken": {"id": "[SEP]", "type_id": 0}},
+      {"Sequence": {"id": "B", "type_id": 1}},
+      {"SpecialToken": {"id": "[SEP]", "type_id": 1}}
+    ],
+    "special_tokens": {
+      "[CLS]": {"id": "[CLS]", "ids": [1], "tokens": ["[CLS]"]},
+      "[SEP]": {"id": "[SEP]", "ids": [2], "tokens": ["[SEP]"]}
+    }
+  },
+  "decoder": {"type": "WordPiece", "prefix": "##", "cleanup": true},
+  "model": {
+    "type": "WordPiece",
+    "unk_token": "[UNK]",
+    "continuing_subword_prefix": "##",
+    "max_input_chars_per_word": 100,
+    "vocab": {"[UNK]": 0, "[CLS]": 1, "[SEP]": 2, "cafe": 3, "##s": 4, ".": 5, "1": 6, "2": 7, "!": 8}
+  }
+}`
+
+func TestToWriter(t *testing.T) {
+	tk, err := FromReader(strings.NewReader(builtinTokenizerJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	extraIds := tokenizer.NewAddedTokenPattern("<extra_id_%d>", 9, 3, true)
+	if err := tk.AddTokenPatterns([]tokenizer.AddedTokenPattern{extraIds}); err != nil {
+		t.Fatal(err)
+	}
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 16, Strategy: tokenizer.OnlyFirst, Stride: 2, Direction: tokenizer.TruncateLeft})
+	tk.WithPadding(&tokenizer.PaddingParams{
+		Strategy:        *tokenizer.NewPaddingStrategy(tokenizer.WithFixed(12)),
+		Direction:       tokenizer.Left,
+		PadToken:        "[UNK]",
+		PadToMultipleOf: 8,
+	})
+
+	var buf bytes.Buffer
+	if err := ToWriter(tk, &buf, true); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := FromReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tk.GetTruncation(), loaded.GetTruncation()) {
+		t.Errorf("want truncation %+v, got %+v", tk.GetTruncation(), loaded.GetTruncation())
+	}
+	if !reflect.DeepEqual(tk.GetPadding(), loaded.GetPadding()) {
+		t.Errorf("want padding %+v, got %+v", tk.GetPadding(), loaded.GetPadding())
+	}
+	if want, got := tk.GetAddedTokens(), loaded.GetAddedTokens(); len(got) != 3 || !reflect.DeepEqual(want[1].TokenConfig(), got[1].TokenConfig()) {
+		t.Errorf("want added tokens %+v, got %+v", want, got)
+	}
+
+	input := "Cafés  cafe.12! <extra_id_1>"
+	want, err := tk.EncodeSingle(input, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loaded.EncodeSingle(input, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Ids, want.Ids) || !reflect.DeepEqual(got.Offsets, want.Offsets) {
+		t.Errorf("want %v %v, got %v %v", want.Tokens, want.Offsets, got.Tokens, got.Offsets)
+	}
+	if wantIds := []int{0, 0, 0, 0, 0, 0, 1, 3, 4, 3, 5, 6, 7, 8, 10, 2}; !reflect.DeepEqual(got.Ids, wantIds) {
+		t.Errorf("want %v, got %v (%q)", wantIds, got.Ids, got.Tokens)
+	}
+	if d1, d2 := tk.Decode(want.Ids, true), loaded.Decode(got.Ids, true); d1 != d2 {
+		t.Errorf("want %q, got %q", d1, d2)
+	}
+
+	file := filepath.Join(t.TempDir(), "tokenizer.json")
+	if err := ToFile(tk, file, false); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = FromFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := tk.GetVocab(true), loaded.GetVocab(true); !reflect.DeepEqual(want, got) {
+		t.Errorf("want vocab %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,63 @@
+package pretrained
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/sugarme/tokenizer"
+)
+
+// SpecialTokensMapFile is the name of the file, next to `tokenizer.json`,
+// holding the special token roles.
+const SpecialTokensMapFile = "special_tokens_map.json"
+
+// CreateSpecialTokenRoles creates the special token roles from the data of a
+// `special_tokens_map.json` (or `tokenizer_config.json`) file, e.g.:
+// "bos_token": "<s>",
+// "eos_token": {"content": "</s>", "lstrip": false, ...},
+// Other keys are ignored.
+func CreateSpecialTokenRoles(config map[string]interface{}) map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	for key, val := range config {
+		var role tokenizer.SpecialTokenRole
+		if err := role.UnmarshalText([]byte(key)); err != nil {
+			continue
+		}
+
+		var content string
+		switch v := val.(type) {
+		case string:
+			content = v
+		case map[string]interface{}:
+			content, _ = v["content"].(string)
+		}
+		if content != "" {
+			roles[role] = content
+		}
+	}
+
+	return roles
+}
+
+// LoadSpecialTokensMap sets the special token roles of the tokenizer from a
+// `special_tokens_map.json` (or `tokenizer_config.json`) file.
+func LoadSpecialTokensMap(tk *tokenizer.Tokenizer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var config map[string]interface{}
+	if err := json.NewDecoder(f).Decode(&config); err != nil {
+		return err
+	}
+
+	roles := CreateSpecialTokenRoles(config)
+	return tk.Update(func(tk *tokenizer.Tokenizer) error {
+		for role, token := range roles {
+			tk.WithSpecialToken(role, token)
+		}
+		return nil
+	})
+}
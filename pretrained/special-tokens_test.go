@@ -0,0 +1,40 @@
+package pretrained
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+)
+
+func TestLoadSpecialTokensMap(t *testing.T) {
+	data := `{
+  "bos_token": "<s>",
+  "eos_token": {"content": "</s>", "lstrip": false, "normalized": false, "rstrip": false, "single_word": false},
+  "pad_token": null,
+  "additional_special_tokens": ["<tool>"]
+}`
+	file := filepath.Join(t.TempDir(), SpecialTokensMapFile)
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tk := BertBaseUncased()
+	if err := LoadSpecialTokensMap(tk, file); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[tokenizer.SpecialTokenRole]string{
+		tokenizer.BosRole: "<s>",
+		tokenizer.EosRole: "</s>",
+		tokenizer.ClsRole: "[CLS]",
+		tokenizer.SepRole: "[SEP]",
+		tokenizer.UnkRole: "[UNK]",
+	}
+	if got := tk.SpecialTokenRoles(); !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %v\n", want)
+		t.Errorf("Got %v\n", got)
+	}
+}
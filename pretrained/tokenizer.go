@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/sugarme/tokenizer"
 )
 
-// FromFile constructs a new Tokenizer from json data file (normally 'tokenizer.json')
+// FromFile constructs a new Tokenizer from json data file (normally 'tokenizer.json').
+// The special token roles are loaded from the `special_tokens_map.json` file
+// in the same directory, if any.
 func FromFile(file string) (*tokenizer.Tokenizer, error) {
 	f, err := os.Open(file)
 	if err != nil {
@@ -21,6 +24,15 @@ func FromFile(file string) (*tokenizer.Tokenizer, error) {
 		err := fmt.Errorf("FromReader: %w", err)
 		return nil, err
 	}
+
+	mapFile := filepath.Join(filepath.Dir(file), SpecialTokensMapFile)
+	if _, err := os.Stat(mapFile); err == nil {
+		if err := LoadSpecialTokensMap(tk, mapFile); err != nil {
+			err = fmt.Errorf("LoadSpecialTokensMap: %w", err)
+			return nil, err
+		}
+	}
+
 	return tk, nil
 }
 
@@ -98,3 +110,79 @@ func FromReader(r io.Reader) (*tokenizer.Tokenizer, error) {
 
 	return tk, nil
 }
+
+// ToConfig returns the `tokenizer.json` config of a tokenizer: its components
+// (see `SerializeNormalizer` and its siblings), added tokens, truncation and
+// padding params. Added tokens defined by an `AddedTokenPattern` are listed
+// individually.
+func ToConfig(tk *tokenizer.Tokenizer) (*tokenizer.Config, error) {
+	var (
+		config = &tokenizer.Config{Version: "1.0"}
+		err    error
+	)
+
+	if config.Normalizer, err = SerializeNormalizer(tk.GetNormalizer()); err != nil {
+		err = fmt.Errorf("SerializeNormalizer: %w", err)
+		return nil, err
+	}
+	if config.PreTokenizer, err = SerializePreTokenizer(tk.GetPreTokenizer()); err != nil {
+		err = fmt.Errorf("SerializePreTokenizer: %w", err)
+		return nil, err
+	}
+	if config.PostProcessor, err = SerializePostProcessor(tk.GetPostProcessor()); err != nil {
+		err = fmt.Errorf("SerializePostProcessor: %w", err)
+		return nil, err
+	}
+	if config.Decoder, err = SerializeDecoder(tk.GetDecoder()); err != nil {
+		err = fmt.Errorf("SerializeDecoder: %w", err)
+		return nil, err
+	}
+	if config.Model, err = SerializeModel(tk.GetModel()); err != nil {
+		err = fmt.Errorf("SerializeModel: %w", err)
+		return nil, err
+	}
+
+	for _, tok := range tk.GetAddedTokens() {
+		config.AddedTokens = append(config.AddedTokens, tok.TokenConfig())
+	}
+
+	config.Truncation = serializeTruncationParams(tk.GetTruncation())
+	if config.Padding, err = serializePaddingParams(tk.GetPadding()); err != nil {
+		err = fmt.Errorf("serializePaddingParams: %w", err)
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ToWriter writes the `tokenizer.json` data of a tokenizer. See `ToConfig`.
+func ToWriter(tk *tokenizer.Tokenizer, w io.Writer, pretty bool) error {
+	config, err := ToConfig(tk)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	if pretty {
+		enc.SetIndent("", "  ")
+	}
+
+	return enc.Encode(config)
+}
+
+// ToFile writes the `tokenizer.json` data of a tokenizer to a file, which
+// `FromFile` loads back. See `ToConfig`.
+func ToFile(tk *tokenizer.Tokenizer, file string, pretty bool) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := ToWriter(tk, f, pretty); err != nil {
+		f.Close()
+		err = fmt.Errorf("ToWriter: %w", err)
+		return err
+	}
+
+	return f.Close()
+}
@@ -11,7 +11,7 @@ func CreateTruncationParams(config map[string]interface{}) (*tokenizer.Truncatio
 	}
 
 	params := util.NewParams(config)
-	// direction := params.Get("direction").(string)
+	direction, _ := params.Get("direction", "Right").(string)
 	maxLen := int(params.Get("max_length").(float64))
 	stride := int(params.Get("stride").(float64))
 	strategyName := params.Get("strategy").(string)
@@ -26,9 +26,47 @@ func CreateTruncationParams(config map[string]interface{}) (*tokenizer.Truncatio
 		strategy = tokenizer.OnlySecond
 	}
 
+	var truncDirection tokenizer.TruncationDirection
+	switch direction {
+	case "Left":
+		truncDirection = tokenizer.TruncateLeft
+	case "Right":
+		truncDirection = tokenizer.TruncateRight
+	}
+
 	return &tokenizer.TruncationParams{
 		MaxLength: maxLen,
 		Strategy:  strategy,
 		Stride:    stride,
+		Direction: truncDirection,
 	}, nil
 }
+
+// serializeTruncationParams returns the json config of truncation params.
+func serializeTruncationParams(p *tokenizer.TruncationParams) map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+
+	var strategy string
+	switch p.Strategy {
+	case tokenizer.LongestFirst:
+		strategy = "LongestFirst"
+	case tokenizer.OnlyFirst:
+		strategy = "OnlyFirst"
+	case tokenizer.OnlySecond:
+		strategy = "OnlySecond"
+	}
+
+	direction := "Right"
+	if p.Direction == tokenizer.TruncateLeft {
+		direction = "Left"
+	}
+
+	return map[string]interface{}{
+		"direction":  direction,
+		"max_length": p.MaxLength,
+		"strategy":   strategy,
+		"stride":     p.Stride,
+	}
+}
@@ -21,6 +21,16 @@ func NewBertProcessing(sep, cls PostToken) (retVal *BertProcessing) {
 	}
 }
 
+// GetSep returns the SEP token.
+func (bp *BertProcessing) GetSep() PostToken {
+	return bp.sep
+}
+
+// GetCls returns the CLS token.
+func (bp *BertProcessing) GetCls() PostToken {
+	return bp.cls
+}
+
 // Implement PostProcessor interface for BertProcessing:
 // =====================================================
 
@@ -32,6 +42,14 @@ func (bp *BertProcessing) AddedTokens(isPair bool) (retVal int) {
 	}
 }
 
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider.
+func (bp *BertProcessing) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	return map[tokenizer.SpecialTokenRole]string{
+		tokenizer.ClsRole: bp.cls.Value,
+		tokenizer.SepRole: bp.sep.Value,
+	}
+}
+
 // Process post-processes input encoding(s) by adding special tokens if specifying.
 func (bp *BertProcessing) Process(encoding, pairEncoding *tokenizer.Encoding, addSpecialTokens bool) (retVal *tokenizer.Encoding) {
 	if !addSpecialTokens {
@@ -112,7 +130,8 @@ func (bp *BertProcessing) addSpecialToken(encoding *tokenizer.Encoding) *tokeniz
 	}
 
 	wordsOpt := tokenizer.WithWordsEncodingOpt(words)
-	return tokenizer.NewEncoding(ids, typeIds, tokens, offsets, specialTokens, attentionMask, []tokenizer.Encoding{}, wordsOpt)
+	rangeOpt := sequenceRangeOpt(0, 1, encoding.Len())
+	return tokenizer.NewEncoding(ids, typeIds, tokens, offsets, specialTokens, attentionMask, []tokenizer.Encoding{}, wordsOpt, rangeOpt)
 }
 
 // pairAddSpecialToken adds special token "[SEP]" to input encoding. It ignores
@@ -151,6 +170,18 @@ func (bp *BertProcessing) pairAddSpecialToken(pairEncoding *tokenizer.Encoding)
 	pairAttentionMask = append(pairAttentionMask, 1)
 
 	pairWordsOpt := tokenizer.WithWordsEncodingOpt(pairWords)
+	pairRangeOpt := sequenceRangeOpt(1, 0, pairEncoding.Len())
+
+	return tokenizer.NewEncoding(pairIds, pairTypeIds, pairTokens, pairOffsets, pairSpecialTokens, pairAttentionMask, []tokenizer.Encoding{}, pairWordsOpt, pairRangeOpt)
+}
+
+// sequenceRangeOpt sets the range of the `n` tokens of sequence `sequenceId`
+// starting at token `start`.
+func sequenceRangeOpt(sequenceId, start, n int) tokenizer.EncodingOpt {
+	ranges := make(map[int]tokenizer.Range)
+	if n > 0 {
+		ranges[sequenceId] = tokenizer.NewRange(start, start+n)
+	}
 
-	return tokenizer.NewEncoding(pairIds, pairTypeIds, pairTokens, pairOffsets, pairSpecialTokens, pairAttentionMask, []tokenizer.Encoding{}, pairWordsOpt)
+	return tokenizer.WithSequenceRangeEncodingOpt(ranges)
 }
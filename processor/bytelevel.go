@@ -15,6 +15,11 @@ func NewByteLevelProcessing(pretok *pretokenizer.ByteLevel) (retVal *ByteLevelPr
 	}
 }
 
+// GetPreTokenizer returns the ByteLevel pre-tokenizer holding the options.
+func (blp *ByteLevelProcessing) GetPreTokenizer() *pretokenizer.ByteLevel {
+	return blp.pretok
+}
+
 // Implement PostProcessor interface for ByteLevelProcessing:
 // =====================================================
 
@@ -43,6 +43,26 @@ func (rp *RobertaProcessing) AddPrefixSpace(addPrefixSpace bool) {
 	rp.addPrefixSpace = addPrefixSpace
 }
 
+// GetSep returns the SEP token.
+func (rp *RobertaProcessing) GetSep() PostToken {
+	return rp.sep
+}
+
+// GetCls returns the CLS token.
+func (rp *RobertaProcessing) GetCls() PostToken {
+	return rp.cls
+}
+
+// GetTrimOffsets returns whether the processor trims offsets.
+func (rp *RobertaProcessing) GetTrimOffsets() bool {
+	return rp.trimOffsets
+}
+
+// GetAddPrefixSpace returns whether the processor adds a prefix space.
+func (rp *RobertaProcessing) GetAddPrefixSpace() bool {
+	return rp.addPrefixSpace
+}
+
 // Implement PostProcessor interface for RobertaProcessing:
 // ========================================================
 
@@ -54,6 +74,17 @@ func (rp *RobertaProcessing) AddedTokens(isPair bool) int {
 	}
 }
 
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider. The CLS and SEP
+// tokens also begin and end sequences.
+func (rp *RobertaProcessing) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	return map[tokenizer.SpecialTokenRole]string{
+		tokenizer.ClsRole: rp.cls.Value,
+		tokenizer.BosRole: rp.cls.Value,
+		tokenizer.SepRole: rp.sep.Value,
+		tokenizer.EosRole: rp.sep.Value,
+	}
+}
+
 // Process post-processes input encoding(s) by adding special tokens if instructed to do so.
 //
 // Specifically, if addSpecialToken=true, it will add special tokens patterns
@@ -168,7 +199,8 @@ func (rp *RobertaProcessing) addSpecialToken(encoding *tokenizer.Encoding) *toke
 	attentionMask = append(attentionMask, 1)
 
 	wordsOpt := tokenizer.WithWordsEncodingOpt(words)
-	return tokenizer.NewEncoding(ids, typeIds, tokens, offsets, specialTokens, attentionMask, []tokenizer.Encoding{}, wordsOpt)
+	rangeOpt := sequenceRangeOpt(0, 1, encoding.Len())
+	return tokenizer.NewEncoding(ids, typeIds, tokens, offsets, specialTokens, attentionMask, []tokenizer.Encoding{}, wordsOpt, rangeOpt)
 }
 
 // addSpecialToken adds special tokens to input pair encoding. It ignores the `Overflowing` field
@@ -216,7 +248,8 @@ func (rp *RobertaProcessing) pairAddSpecialToken(pair *tokenizer.Encoding) *toke
 	pairAttentionMask = append(pairAttentionMask, 1)
 
 	pairWordsOpt := tokenizer.WithWordsEncodingOpt(pairWords)
-	return tokenizer.NewEncoding(pairIds, pairTypeIds, pairTokens, pairOffsets, pairSpecialTokens, pairAttentionMask, []tokenizer.Encoding{}, pairWordsOpt)
+	pairRangeOpt := sequenceRangeOpt(1, 1, pair.Len())
+	return tokenizer.NewEncoding(pairIds, pairTypeIds, pairTokens, pairOffsets, pairSpecialTokens, pairAttentionMask, []tokenizer.Encoding{}, pairWordsOpt, pairRangeOpt)
 }
 
 // TODO: implement Serialize interface for RobertaProcessing
@@ -12,6 +12,21 @@ func NewSequence(processors []tokenizer.PostProcessor) *Sequence {
 	return &Sequence{processors}
 }
 
+// Processors returns the post-processors of the sequence.
+func (seq *Sequence) Processors() []tokenizer.PostProcessor {
+	return seq.processors
+}
+
+// Clone implements tokenizer.Cloner.
+func (seq *Sequence) Clone() tokenizer.PostProcessor {
+	processors := make([]tokenizer.PostProcessor, len(seq.processors))
+	for i, p := range seq.processors {
+		processors[i] = tokenizer.CloneComponent(p)
+	}
+
+	return NewSequence(processors)
+}
+
 // Implement tokenizer.PostProcessor for Sequence
 
 func (seq *Sequence) AddedTokens(isPair bool) (retVal int) {
@@ -23,6 +38,25 @@ func (seq *Sequence) AddedTokens(isPair bool) (retVal int) {
 	return count
 }
 
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider. The roles of the
+// first processors take precedence.
+func (seq *Sequence) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	for _, p := range seq.processors {
+		sp, ok := p.(tokenizer.SpecialTokensProvider)
+		if !ok {
+			continue
+		}
+		for role, token := range sp.SpecialTokenRoles() {
+			if _, ok := roles[role]; !ok {
+				roles[role] = token
+			}
+		}
+	}
+
+	return roles
+}
+
 func (seq *Sequence) Process(encoding, pairEncoding *tokenizer.Encoding, addSpecialTokens bool) (retVal *tokenizer.Encoding) {
 	// return blp.pretok.Process(encoding, pairEncoding, addSpecialTokens)
 	var encodings *tokenizer.Encoding = encoding
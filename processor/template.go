@@ -15,8 +15,26 @@ type SequenceEnum int
 const (
 	A SequenceEnum = iota
 	B
+	C
 )
 
+// sequenceEnum returns the sequence identified by a letter: "A" (or "a") is
+// the first sequence, "B" the second, and so on.
+func sequenceEnum(s string) (SequenceEnum, bool) {
+	if len(s) != 1 {
+		return 0, false
+	}
+
+	switch c := s[0]; {
+	case c >= 'A' && c <= 'Z':
+		return SequenceEnum(c - 'A'), true
+	case c >= 'a' && c <= 'z':
+		return SequenceEnum(c - 'a'), true
+	}
+
+	return 0, false
+}
+
 type Piece interface {
 	// ExtractId(s string) Piece
 	WithTypeId(typeId int)
@@ -46,16 +64,17 @@ func extractId(s string) (Piece, error) {
 		if err == nil {
 			isNum = true
 		}
+		seq, isSeq := sequenceEnum(rest)
 
 		switch {
-		case rest == "", rest == "A", rest == "a":
+		case rest == "":
 			p = &SequencePiece{
 				Id:     A,
 				TypeId: 0,
 			}
-		case rest == "B", rest == "b":
+		case isSeq:
 			p = &SequencePiece{
-				Id:     B,
+				Id:     seq,
 				TypeId: 0,
 			}
 
@@ -80,10 +99,8 @@ func extractId(s string) (Piece, error) {
 }
 
 func NewSequencePiece(id string, typeId int) *SequencePiece {
-	var seqEnum SequenceEnum
-	if id == "A" {
-		seqEnum = A
-	} else {
+	seqEnum, ok := sequenceEnum(id)
+	if !ok {
 		seqEnum = B
 	}
 	return &SequencePiece{
@@ -175,6 +192,29 @@ func NewSpecialTokenFrom(s string, id int) *SpecialToken {
 
 type Template []Piece
 
+// clone returns a deep copy of the template.
+func (t Template) clone() Template {
+	if t == nil {
+		return nil
+	}
+
+	clone := make(Template, len(t))
+	for i, piece := range t {
+		switch p := piece.(type) {
+		case *SequencePiece:
+			c := *p
+			clone[i] = &c
+		case *SpecialTokenPiece:
+			c := *p
+			clone[i] = &c
+		default:
+			clone[i] = piece
+		}
+	}
+
+	return clone
+}
+
 func NewTemplateFromOne(s string) (Template, error) {
 	parts := strings.Split(s, " ")
 
@@ -272,6 +312,21 @@ func (t *Tokens) GetItemByKey(id string) (SpecialToken, bool) {
 	return val, ok
 }
 
+// clone returns a deep copy of the tokens.
+func (t *Tokens) clone() *Tokens {
+	clone := &Tokens{
+		TokenMap:    make(map[string]SpecialToken, len(t.TokenMap)),
+		orderedKeys: append([]string(nil), t.orderedKeys...),
+	}
+	for k, tok := range t.TokenMap {
+		tok.Ids = append([]int(nil), tok.Ids...)
+		tok.Tokens = append([]string(nil), tok.Tokens...)
+		clone.TokenMap[k] = tok
+	}
+
+	return clone
+}
+
 // / This PostProcessor takes care of processing each input `Encoding` by applying
 // / the corresponding template, before merging them in the final Encoding.
 // /
@@ -296,6 +351,9 @@ type TemplateProcessing struct {
 	AddedSingle   int
 	AddedPair     int
 	SpecialTokens *Tokens
+	// Multi holds templates for inputs of more than two sequences, keyed by
+	// the number of sequences. E.g. "[CLS] $A [SEP] $B:1 [SEP]:1 $C:2 [SEP]:2" for 3.
+	Multi map[int]Template
 }
 
 type TemplateProcessingDeserializer struct {
@@ -386,6 +444,19 @@ func (b *TemplateProcessingBuilder) NewPair(v interface{}) {
 	b.updateAddedTokens()
 }
 
+// NewMulti sets the template used for inputs of `n` sequences (more than two).
+func (b *TemplateProcessingBuilder) NewMulti(n int, v interface{}) {
+	tpl, err := NewTemplate(v)
+	if err != nil {
+		panic("err")
+	}
+
+	if b.Multi == nil {
+		b.Multi = make(map[int]Template)
+	}
+	b.Multi[n] = tpl
+}
+
 func (b *TemplateProcessingBuilder) NewSpecialTokens(tokens []tokenizer.Token) {
 	b.SpecialTokens = NewTokens(tokens)
 	b.updateAddedTokens()
@@ -512,14 +583,10 @@ func (tp *TemplateProcessing) ApplyTemplate(template []Piece, encodings []tokeni
 			sp := piece.(*SequencePiece)
 			id := sp.Id
 			typeId := sp.TypeId
-			i := 0
-			if id != A {
-				i = 1
-			}
 			encoding := encodings[id]
 			typeIds := util.Repeat(typeId, encoding.Len())
 			encoding.SetTypeIds(typeIds)
-			encoding.SetSequenceIds(i)
+			encoding.SetSequenceIds(int(id))
 
 			finalEncodings = append(finalEncodings, encoding)
 
@@ -565,6 +632,57 @@ func (tp *TemplateProcessing) AddedTokens(isPair bool) int {
 	return tp.AddedSingle
 }
 
+// Clone implements tokenizer.Cloner.
+func (tp *TemplateProcessing) Clone() tokenizer.PostProcessor {
+	clone := *tp
+	clone.Single = tp.Single.clone()
+	clone.Pair = tp.Pair.clone()
+	if tp.SpecialTokens != nil {
+		clone.SpecialTokens = tp.SpecialTokens.clone()
+	}
+	if tp.Multi != nil {
+		clone.Multi = make(map[int]Template, len(tp.Multi))
+		for n, t := range tp.Multi {
+			clone.Multi[n] = t.clone()
+		}
+	}
+
+	return &clone
+}
+
+// SpecialTokenRoles implements tokenizer.SpecialTokensProvider. Only the
+// "[CLS]" token starting the single template and the "[SEP]" token ending it
+// are known: other roles (e.g. BOS and EOS) can't be told from a template and
+// are set from `special_tokens_map.json` or with `Tokenizer.WithSpecialToken`.
+func (tp *TemplateProcessing) SpecialTokenRoles() map[tokenizer.SpecialTokenRole]string {
+	roles := make(map[tokenizer.SpecialTokenRole]string)
+	if len(tp.Single) < 2 || tp.SpecialTokens == nil {
+		return roles
+	}
+
+	// content returns the content of a single token special piece.
+	content := func(piece Piece) string {
+		sp, ok := piece.(*SpecialTokenPiece)
+		if !ok {
+			return ""
+		}
+		tok, ok := tp.SpecialTokens.GetItemByKey(sp.Id)
+		if !ok || len(tok.Tokens) != 1 {
+			return ""
+		}
+		return tok.Tokens[0]
+	}
+
+	if tok := content(tp.Single[0]); tok == "[CLS]" {
+		roles[tokenizer.ClsRole] = tok
+	}
+	if tok := content(tp.Single[len(tp.Single)-1]); tok == "[SEP]" {
+		roles[tokenizer.SepRole] = tok
+	}
+
+	return roles
+}
+
 func (tp *TemplateProcessing) Process(encoding, pairEncoding *tokenizer.Encoding, addSpecialTokens bool) *tokenizer.Encoding {
 	encodings := tokenizer.PrepareEncodings(encoding, pairEncoding)
 	var template Template
@@ -581,3 +699,45 @@ func (tp *TemplateProcessing) Process(encoding, pairEncoding *tokenizer.Encoding
 
 	return tokenizer.MergeEncodings(appliedEncodings, false)
 }
+
+// Implement tokenizer.MultiPostProcessor for TemplateProcessing:
+// ----------------------------------------------------------------
+
+var _ tokenizer.MultiPostProcessor = new(TemplateProcessing)
+
+func (tp *TemplateProcessing) AddedTokensMulti(n int) int {
+	switch n {
+	case 1:
+		return tp.AddedSingle
+	case 2:
+		return tp.AddedPair
+	}
+
+	return countAdded(tp.Multi[n], tp.SpecialTokens)
+}
+
+func (tp *TemplateProcessing) ProcessMulti(encodings []*tokenizer.Encoding, addSpecialTokens bool) (*tokenizer.Encoding, error) {
+	var template Template
+	switch len(encodings) {
+	case 1:
+		template = tp.Single
+	case 2:
+		template = tp.Pair
+	default:
+		tpl, ok := tp.Multi[len(encodings)]
+		if !ok {
+			return nil, fmt.Errorf("No template for %d sequences", len(encodings))
+		}
+		template = tpl
+	}
+
+	for _, piece := range template {
+		if sp, ok := piece.(*SequencePiece); ok && int(sp.Id) >= len(encodings) {
+			return nil, fmt.Errorf("Template uses sequence %d but got %d sequences", sp.Id, len(encodings))
+		}
+	}
+
+	appliedEncodings := tp.ApplyTemplate(template, tokenizer.PrepareMultiEncodings(encodings), addSpecialTokens)
+
+	return tokenizer.MergeEncodings(appliedEncodings, false), nil
+}
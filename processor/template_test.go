@@ -422,3 +422,72 @@ func TestTemplateProcessingOverflowing(t *testing.T) {
 		t.Errorf("\nwant %#v, \ngot %#v", wantPairEncoding, gotPairEncoding)
 	}
 }
+
+func TestTemplateProcessingMulti(t *testing.T) {
+	builder := getBertTemplate().Builder()
+	builder.NewMulti(3, "[CLS] $A [SEP] $B:1 [SEP]:1 $C:2 [SEP]:2")
+	processor := builder.Build()
+
+	if got := processor.AddedTokensMulti(3); got != 4 {
+		t.Errorf("want 4 added tokens, got %v", got)
+	}
+
+	encodings := []*tokenizer.Encoding{
+		tokenizer.NewEncodingFromTokens([]tokenizer.Token{{Id: 12, Value: "query", Offsets: []int{0, 5}}}, 0),
+		tokenizer.NewEncodingFromTokens([]tokenizer.Token{{Id: 13, Value: "title", Offsets: []int{0, 5}}}, 1),
+		tokenizer.NewEncodingFromTokens([]tokenizer.Token{
+			{Id: 14, Value: "long", Offsets: []int{0, 4}},
+			{Id: 15, Value: "body", Offsets: []int{5, 9}},
+		}, 2),
+	}
+
+	got, err := processor.ProcessMulti(encodings, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{1, 12, 0, 13, 0, 14, 15, 0}; !reflect.DeepEqual(want, got.Ids) {
+		t.Errorf("want ids %v, got %v", want, got.Ids)
+	}
+	if want := []int{0, 0, 0, 1, 1, 2, 2, 2}; !reflect.DeepEqual(want, got.TypeIds) {
+		t.Errorf("want type ids %v, got %v", want, got.TypeIds)
+	}
+	wantRanges := map[int]tokenizer.Range{
+		0: tokenizer.NewRange(1, 2),
+		1: tokenizer.NewRange(3, 4),
+		2: tokenizer.NewRange(5, 7),
+	}
+	if !reflect.DeepEqual(wantRanges, got.SequenceRanges) {
+		t.Errorf("want sequence ranges %v, got %v", wantRanges, got.SequenceRanges)
+	}
+
+	if _, err := processor.ProcessMulti(append(encodings, encodings[0]), true); err == nil {
+		t.Errorf("want error for 4 sequences without template")
+	}
+}
+
+func TestTemplateProcessingSpecialTokenRoles(t *testing.T) {
+	got := getBertTemplate().SpecialTokenRoles()
+	want := map[tokenizer.SpecialTokenRole]string{
+		tokenizer.ClsRole: "[CLS]",
+		tokenizer.SepRole: "[SEP]",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v\n", want, got)
+	}
+
+	builder := DefaultTemplateProcessing().Builder()
+	builder.NewSingle([]string{"<s>", "$A", "</s>"})
+	builder.NewPair("<s> $A </s> $B </s>")
+	builder.NewSpecialTokens([]tokenizer.Token{
+		{Id: 1, Value: "<s>"},
+		{Id: 2, Value: "</s>"},
+	})
+
+	// Other roles are not guessed
+	got = builder.Build().SpecialTokenRoles()
+	want = map[tokenizer.SpecialTokenRole]string{}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v\n", want, got)
+	}
+}
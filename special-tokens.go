@@ -0,0 +1,219 @@
+package tokenizer
+
+import (
+	"fmt"
+)
+
+// SpecialTokenRole is the role of a special token, so that model-agnostic code
+// can ask for e.g. "the EOS token" whatever its content.
+type SpecialTokenRole int
+
+const (
+	// BosRole is the beginning of sequence token, e.g. "<s>".
+	BosRole SpecialTokenRole = iota
+	// EosRole is the end of sequence token, e.g. "</s>".
+	EosRole
+	// UnkRole is the unknown token, e.g. "[UNK]".
+	UnkRole
+	// SepRole is the separator token, e.g. "[SEP]".
+	SepRole
+	// PadRole is the padding token, e.g. "[PAD]".
+	PadRole
+	// ClsRole is the classifier token, e.g. "[CLS]".
+	ClsRole
+	// MaskRole is the mask token, e.g. "[MASK]".
+	MaskRole
+)
+
+// specialTokenRoleNames are the keys of `special_tokens_map.json`.
+var specialTokenRoleNames = map[SpecialTokenRole]string{
+	BosRole:  "bos_token",
+	EosRole:  "eos_token",
+	UnkRole:  "unk_token",
+	SepRole:  "sep_token",
+	PadRole:  "pad_token",
+	ClsRole:  "cls_token",
+	MaskRole: "mask_token",
+}
+
+// String implements fmt.Stringer.
+func (r SpecialTokenRole) String() string {
+	if name, ok := specialTokenRoleNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("SpecialTokenRole(%d)", int(r))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r SpecialTokenRole) MarshalText() ([]byte, error) {
+	if _, ok := specialTokenRoleNames[r]; !ok {
+		return nil, fmt.Errorf("Invalid special token role (%v).\n", int(r))
+	}
+
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *SpecialTokenRole) UnmarshalText(text []byte) error {
+	for role, name := range specialTokenRoleNames {
+		if name == string(text) {
+			*r = role
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid special token role (%q).\n", text)
+}
+
+// SpecialTokensProvider is implemented by components (models, post-processors)
+// knowing the roles of the special tokens they use, e.g. the unknown token of a
+// model or the CLS and SEP tokens of `BertProcessing`.
+type SpecialTokensProvider interface {
+	SpecialTokenRoles() map[SpecialTokenRole]string
+}
+
+// resolveSpecialTokenRoles resolves the special token roles from, by order of
+// precedence, the roles set on the tokenizer, the post-processor, the padding
+// params and the model.
+func (c *tokenizerConfig) resolveSpecialTokenRoles() {
+	roles := make(map[SpecialTokenRole]string)
+	add := func(role SpecialTokenRole, token string) {
+		if _, ok := roles[role]; !ok && token != "" {
+			roles[role] = token
+		}
+	}
+
+	for role, token := range c.specialTokenRoles {
+		add(role, token)
+	}
+	if p, ok := c.postProcessor.(SpecialTokensProvider); ok {
+		for role, token := range p.SpecialTokenRoles() {
+			add(role, token)
+		}
+	}
+	if c.padding != nil {
+		add(PadRole, c.padding.PadToken)
+	}
+	if m, ok := c.model.(SpecialTokensProvider); ok {
+		for role, token := range m.SpecialTokenRoles() {
+			add(role, token)
+		}
+	}
+
+	c.resolvedRoles = roles
+}
+
+// isRoleToken reports whether the token has a special token role other than
+// `UnkRole`: the unknown token stands for some text, so it is not skipped as a
+// special token when decoding unless it has been added as a special token.
+func (c *tokenizerConfig) isRoleToken(token string) bool {
+	for role, t := range c.resolvedRoles {
+		if t == token && role != UnkRole {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithSpecialToken sets the token having the given role, taking precedence over
+// the roles known by the components. An empty token unsets the role.
+func (t *Tokenizer) WithSpecialToken(role SpecialTokenRole, token string) {
+	t.update(func(c *tokenizerConfig) {
+		if token == "" {
+			delete(c.specialTokenRoles, role)
+			return
+		}
+		c.specialTokenRoles[role] = token
+	})
+}
+
+// SpecialToken returns the token having the given role.
+//
+// Roles set with `WithSpecialToken` (e.g. from `special_tokens_map.json`) come
+// first, then the roles known by the post-processor (CLS, SEP...), the padding
+// token and the unknown token of the model.
+func (t *Tokenizer) SpecialToken(role SpecialTokenRole) (token string, ok bool) {
+	token, ok = t.load().resolvedRoles[role]
+	return token, ok
+}
+
+// SpecialTokenId returns the id of the token having the given role.
+func (t *Tokenizer) SpecialTokenId(role SpecialTokenRole) (id int, ok bool) {
+	c := t.load()
+	token, ok := c.resolvedRoles[role]
+	if !ok {
+		return 0, false
+	}
+
+	return c.addedVocabulary.TokenToId(token, c.model)
+}
+
+// SpecialTokenRoles returns the tokens of all the known roles.
+func (t *Tokenizer) SpecialTokenRoles() map[SpecialTokenRole]string {
+	roles := make(map[SpecialTokenRole]string)
+	for role, token := range t.load().resolvedRoles {
+		roles[role] = token
+	}
+
+	return roles
+}
+
+// BosToken returns the beginning of sequence token.
+func (t *Tokenizer) BosToken() (string, bool) { return t.SpecialToken(BosRole) }
+
+// BosTokenId returns the id of the beginning of sequence token.
+func (t *Tokenizer) BosTokenId() (int, bool) { return t.SpecialTokenId(BosRole) }
+
+// EosToken returns the end of sequence token.
+func (t *Tokenizer) EosToken() (string, bool) { return t.SpecialToken(EosRole) }
+
+// EosTokenId returns the id of the end of sequence token.
+func (t *Tokenizer) EosTokenId() (int, bool) { return t.SpecialTokenId(EosRole) }
+
+// UnkToken returns the unknown token.
+func (t *Tokenizer) UnkToken() (string, bool) { return t.SpecialToken(UnkRole) }
+
+// UnkTokenId returns the id of the unknown token.
+func (t *Tokenizer) UnkTokenId() (int, bool) { return t.SpecialTokenId(UnkRole) }
+
+// SepToken returns the separator token.
+func (t *Tokenizer) SepToken() (string, bool) { return t.SpecialToken(SepRole) }
+
+// SepTokenId returns the id of the separator token.
+func (t *Tokenizer) SepTokenId() (int, bool) { return t.SpecialTokenId(SepRole) }
+
+// PadToken returns the padding token.
+func (t *Tokenizer) PadToken() (string, bool) { return t.SpecialToken(PadRole) }
+
+// PadTokenId returns the id of the padding token.
+func (t *Tokenizer) PadTokenId() (int, bool) { return t.SpecialTokenId(PadRole) }
+
+// ClsToken returns the classifier token.
+func (t *Tokenizer) ClsToken() (string, bool) { return t.SpecialToken(ClsRole) }
+
+// ClsTokenId returns the id of the classifier token.
+func (t *Tokenizer) ClsTokenId() (int, bool) { return t.SpecialTokenId(ClsRole) }
+
+// MaskToken returns the mask token.
+func (t *Tokenizer) MaskToken() (string, bool) { return t.SpecialToken(MaskRole) }
+
+// MaskTokenId returns the id of the mask token.
+func (t *Tokenizer) MaskTokenId() (int, bool) { return t.SpecialTokenId(MaskRole) }
+
+// withPadToken fills the padding token and id from the `PadRole` token when
+// the given params have no padding token.
+func (c *tokenizerConfig) withPadToken(padding *PaddingParams) {
+	if padding == nil || padding.PadToken != "" {
+		return
+	}
+	token, ok := c.resolvedRoles[PadRole]
+	if !ok {
+		return
+	}
+	if id, ok := c.addedVocabulary.TokenToId(token, c.model); ok {
+		padding.PadToken = token
+		padding.PadId = id
+	}
+}
@@ -0,0 +1,83 @@
+package tokenizer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func TestSpecialTokenRoles(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	// From the post-processor and the model
+	want := map[tokenizer.SpecialTokenRole]string{
+		tokenizer.ClsRole: "[CLS]",
+		tokenizer.SepRole: "[SEP]",
+		tokenizer.UnkRole: "[UNK]",
+	}
+	if got := tk.SpecialTokenRoles(); !reflect.DeepEqual(want, got) {
+		t.Errorf("Want %v\n", want)
+		t.Errorf("Got %v\n", got)
+	}
+	if id, ok := tk.SepTokenId(); !ok || id != 102 {
+		t.Errorf("Want SEP id 102, got %v, %v\n", id, ok)
+	}
+	if _, ok := tk.EosTokenId(); ok {
+		t.Errorf("Want no EOS token\n")
+	}
+
+	// Set roles take precedence
+	tk.WithSpecialToken(tokenizer.MaskRole, "[MASK]")
+	tk.WithSpecialToken(tokenizer.SepRole, "[unused0]")
+	if tok, _ := tk.MaskToken(); tok != "[MASK]" {
+		t.Errorf("Want '[MASK]', got %q\n", tok)
+	}
+	if id, _ := tk.SepTokenId(); id != 1 {
+		t.Errorf("Want SEP id 1, got %v\n", id)
+	}
+	tk.WithSpecialToken(tokenizer.SepRole, "")
+	if id, _ := tk.SepTokenId(); id != 102 {
+		t.Errorf("Want SEP id 102 back, got %v\n", id)
+	}
+}
+
+func TestSpecialTokenRoles_PaddingAndDecoding(t *testing.T) {
+	tk := newWordLevelTokenizer(t, []string{"<unk>", "<pad>", "</s>", "hello", "world"}, nil)
+	tk.WithPreTokenizer(nil)
+	tk.WithSpecialToken(tokenizer.PadRole, "<pad>")
+	tk.WithSpecialToken(tokenizer.EosRole, "</s>")
+
+	// Padding without a padding token uses the PAD token
+	tk.WithPadding(&tokenizer.PaddingParams{Strategy: *tokenizer.NewPaddingStrategy(tokenizer.WithFixed(3)), Direction: tokenizer.Right})
+	en, err := tk.EncodeSingle("hello", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 1, 1}; !reflect.DeepEqual(want, en.Ids) {
+		t.Errorf("Want ids %v, got %v\n", want, en.Ids)
+	}
+
+	// Role tokens are special tokens when decoding, except the unknown token
+	got := tk.Decode([]int{3, 2, 1, 1}, true)
+	if got != "hello" {
+		t.Errorf("Want 'hello', got %q\n", got)
+	}
+	if got := tk.Decode([]int{3, 0, 2}, true); got != "hello <unk>" {
+		t.Errorf("Want 'hello <unk>', got %q\n", got)
+	}
+}
+
+func TestSpecialTokenRole_Text(t *testing.T) {
+	var role tokenizer.SpecialTokenRole
+	if err := role.UnmarshalText([]byte("eos_token")); err != nil || role != tokenizer.EosRole {
+		t.Errorf("Want EosRole, got %v, %v\n", role, err)
+	}
+	if err := role.UnmarshalText([]byte("additional_special_tokens")); err == nil {
+		t.Errorf("Want an error for an unknown role\n")
+	}
+	if got := tokenizer.PadRole.String(); got != "pad_token" {
+		t.Errorf("Want 'pad_token', got %q\n", got)
+	}
+}
@@ -0,0 +1,232 @@
+package tokenizer
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrStopped is returned by `StopSequenceMatcher.Step` once a stop sequence has matched.
+var ErrStopped = errors.New("stop sequence matcher: a stop sequence has already matched")
+
+// StopMatch describes where a stop sequence has been found.
+type StopMatch struct {
+	// Sequence is the stop sequence that matched
+	Sequence string
+	// Start and End are the byte offsets of the stop sequence in the decoded text
+	Start int
+	End   int
+	// StartToken and EndToken are the indexes (among the ids given to `Step`)
+	// of the tokens producing the first and the last byte of the stop sequence
+	StartToken int
+	EndToken   int
+}
+
+// StopSequenceMatcher decodes ids incrementally and stops as soon as the decoded
+// text contains one of the given stop sequences, even if the stop sequence spans
+// several tokens or starts in the middle of a token.
+//
+// Text that could still be the beginning of a stop sequence is held back until
+// it is known whether the stop sequence completes.
+//
+// Example:
+//
+//	m := tokenizer.NewStopSequenceMatcher(tk, []string{"\nUser:"}, tokenizer.WithSkipSpecialTokens(true))
+//	for _, id := range generatedIds {
+//		text, match, err := m.Step(id)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Print(text)
+//		if match != nil {
+//			break
+//		}
+//	}
+type StopSequenceMatcher struct {
+	stream *DecodeStream
+	stops  []string
+
+	// decoded text not yet returned to the caller
+	pending string
+	// number of bytes already returned to the caller
+	released int
+	// byte offset of the end of the text produced by each token
+	tokenEnds []int
+	match     *StopMatch
+}
+
+// NewStopSequenceMatcher creates a StopSequenceMatcher for the given tokenizer
+// and stop sequences. Empty stop sequences are ignored.
+func NewStopSequenceMatcher(tk *Tokenizer, stops []string, opts ...DecodeOption) *StopSequenceMatcher {
+	var nonEmpty []string
+	for _, s := range stops {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+
+	return &StopSequenceMatcher{
+		stream: NewDecodeStream(tk, opts...),
+		stops:  nonEmpty,
+	}
+}
+
+// Step adds the given id and returns the text that is safe to emit. If a stop
+// sequence has been completed, the returned text ends right before it and the
+// match is returned.
+func (m *StopSequenceMatcher) Step(id int) (string, *StopMatch, error) {
+	if m.match != nil {
+		return "", nil, ErrStopped
+	}
+
+	// Window of the stream before the step, to attribute held back text
+	window := append([]int(nil), m.stream.ids...)
+	prefixIndex, prefix := m.stream.prefixIndex, m.stream.prefix
+
+	text, err := m.stream.Step(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	start := m.released + len(m.pending)
+	m.tokenEnds = append(m.tokenEnds, start+len(text))
+	if text != "" && prefixIndex < len(window) {
+		if err := m.resolveHeldBack(window, prefixIndex, prefix, start, text); err != nil {
+			return "", nil, err
+		}
+	}
+	m.pending += text
+
+	if match := m.find(); match != nil {
+		m.match = match
+		out := m.pending[:match.Start-m.released]
+		m.released += len(out)
+		m.pending = ""
+		return out, match, nil
+	}
+
+	// Release everything that can't be the start of a stop sequence
+	n := len(m.pending) - m.heldBack()
+	out := m.pending[:n]
+	m.pending = m.pending[n:]
+	m.released += n
+
+	return out, nil, nil
+}
+
+// Flush returns the text held back by the matcher. It is intended to be called
+// when generation ends without any stop sequence.
+func (m *StopSequenceMatcher) Flush() (string, error) {
+	if m.match != nil {
+		return "", nil
+	}
+
+	text, err := m.stream.Flush()
+	if err != nil {
+		return "", err
+	}
+
+	out := m.pending + text
+	m.released += len(out)
+	m.pending = ""
+
+	return out, nil
+}
+
+// Match returns the stop sequence match if any.
+func (m *StopSequenceMatcher) Match() *StopMatch {
+	return m.match
+}
+
+// find returns the earliest (then longest) stop sequence in the pending text.
+func (m *StopSequenceMatcher) find() *StopMatch {
+	var (
+		best     *StopMatch
+		bestStop string
+	)
+
+	for _, stop := range m.stops {
+		idx := strings.Index(m.pending, stop)
+		if idx < 0 {
+			continue
+		}
+		start := m.released + idx
+		if best == nil || start < best.Start || (start == best.Start && len(stop) > len(bestStop)) {
+			best = &StopMatch{Sequence: stop, Start: start, End: start + len(stop)}
+			bestStop = stop
+		}
+	}
+
+	if best != nil {
+		best.StartToken = m.tokenAt(best.Start)
+		best.EndToken = m.tokenAt(best.End - 1)
+	}
+
+	return best
+}
+
+// heldBack returns the length of the longest suffix of the pending text that is
+// a proper prefix of a stop sequence.
+func (m *StopSequenceMatcher) heldBack() int {
+	longest := 0
+	for _, stop := range m.stops {
+		max := len(stop) - 1
+		if max > len(m.pending) {
+			max = len(m.pending)
+		}
+		for n := max; n > longest; n-- {
+			if strings.HasSuffix(m.pending, stop[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+
+	return longest
+}
+
+// resolveHeldBack sets the end offsets of the tokens whose text the stream held
+// back (e.g. incomplete UTF-8) and has just emitted along with the last token.
+// Each held back token ends where its own decoded text stops matching the
+// emitted text. With decoders replacing incomplete bytes (e.g. byte fallback),
+// a character is therefore credited to the token completing it.
+func (m *StopSequenceMatcher) resolveHeldBack(window []int, prefixIndex int, prefix string, start int, text string) error {
+	full := prefix + text
+	held := len(window) - prefixIndex
+	first := len(m.tokenEnds) - 1 - held
+
+	end := 0
+	for k := 0; k < held; k++ {
+		decoded, err := m.stream.decode(window[:prefixIndex+k+1])
+		if err != nil {
+			return err
+		}
+		n := commonPrefixLen(decoded, full) - len(prefix)
+		if n > end {
+			end = n
+		}
+		if end > len(text) {
+			end = len(text)
+		}
+		m.tokenEnds[first+k] = start + end
+	}
+
+	return nil
+}
+
+// commonPrefixLen returns the length of the longest common prefix of a and b.
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+
+	return n
+}
+
+// tokenAt returns the index of the token that produced the byte at the given offset.
+func (m *StopSequenceMatcher) tokenAt(offset int) int {
+	return sort.Search(len(m.tokenEnds), func(i int) bool {
+		return m.tokenEnds[i] > offset
+	})
+}
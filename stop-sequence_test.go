@@ -0,0 +1,101 @@
+package tokenizer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretokenizer"
+)
+
+func TestStopSequenceMatcher(t *testing.T) {
+	metaspace := pretokenizer.NewMetaspace("▁", true)
+	tokens := []string{"<unk>", "▁Hello", "▁world", "!\n", "Us", "er", ":", "▁more"}
+	tk := newWordLevelTokenizer(t, tokens, metaspace)
+
+	m := tokenizer.NewStopSequenceMatcher(tk, []string{"\nUser:", "zzz"})
+
+	var (
+		got   []string
+		match *tokenizer.StopMatch
+	)
+	for _, id := range []int{1, 2, 3, 4, 5, 6, 7} {
+		text, mt, err := m.Step(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, text)
+		if mt != nil {
+			match = mt
+			break
+		}
+	}
+
+	want := []string{"Hello", " world", "!", "", "", ""}
+	if len(got) != len(want) {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %d: want %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	wantMatch := tokenizer.StopMatch{Sequence: "\nUser:", Start: 12, End: 18, StartToken: 2, EndToken: 5}
+	if match == nil || *match != wantMatch {
+		t.Errorf("want %+v, got %+v", wantMatch, match)
+	}
+
+	if _, _, err := m.Step(7); !errors.Is(err, tokenizer.ErrStopped) {
+		t.Errorf("want ErrStopped, got %v", err)
+	}
+}
+
+func TestStopSequenceMatcher_NoMatch(t *testing.T) {
+	metaspace := pretokenizer.NewMetaspace("▁", true)
+	tokens := []string{"<unk>", "▁Hello", "▁world", "!\n", "Us"}
+	tk := newWordLevelTokenizer(t, tokens, metaspace)
+
+	m := tokenizer.NewStopSequenceMatcher(tk, []string{"\nUser:"})
+	var text string
+	for _, id := range []int{1, 2, 3, 4} {
+		s, match, err := m.Step(id)
+		if err != nil || match != nil {
+			t.Fatalf("unexpected match %v or error %v", match, err)
+		}
+		text += s
+	}
+	if text != "Hello world!" {
+		t.Errorf("want %q, got %q", "Hello world!", text)
+	}
+
+	rest, err := m.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text+rest != "Hello world!\nUs" {
+		t.Errorf("want %q, got %q", "Hello world!\nUs", text+rest)
+	}
+}
+
+func TestStopSequenceMatcher_MultiByte(t *testing.T) {
+	bc := pretokenizer.BytesChar
+	euro := []byte("€")
+	tokens := []string{"<unk>", "Hi", bc[' '] + bc[euro[0]], bc[euro[1]] + bc[euro[2]]}
+	tk := newWordLevelTokenizer(t, tokens, pretokenizer.NewByteLevel())
+
+	m := tokenizer.NewStopSequenceMatcher(tk, []string{" €"})
+	var match *tokenizer.StopMatch
+	for _, id := range []int{1, 2, 3} {
+		_, mt, err := m.Step(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		match = mt
+	}
+
+	want := tokenizer.StopMatch{Sequence: " €", Start: 2, End: 6, StartToken: 1, EndToken: 2}
+	if match == nil || *match != want {
+		t.Errorf("want %+v, got %+v", want, match)
+	}
+}
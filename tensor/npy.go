@@ -0,0 +1,193 @@
+package tensor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const npyMagic = "\x93NUMPY"
+
+var (
+	npyDescrRe   = regexp.MustCompile(`'descr':\s*'([^']*)'`)
+	npyFortranRe = regexp.MustCompile(`'fortran_order':\s*(True|False)`)
+	npyShapeRe   = regexp.MustCompile(`'shape':\s*\(([^)]*)\)`)
+)
+
+// WriteNpy writes the tensor in NumPy `.npy` format (version 1.0, little-endian int64).
+func WriteNpy(w io.Writer, t *Tensor) error {
+	if len(t.Data) != numElements(t.Shape) {
+		return fmt.Errorf("tensor: shape %v doesn't match %d elements", t.Shape, len(t.Data))
+	}
+
+	dims := make([]string, len(t.Shape))
+	for i, d := range t.Shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	shape := strings.Join(dims, ", ")
+	if len(t.Shape) == 1 {
+		shape += ","
+	}
+	header := fmt.Sprintf("{'descr': '<i8', 'fortran_order': False, 'shape': (%s), }", shape)
+
+	// Pad the header with spaces so that the data is 64-byte aligned
+	preamble := len(npyMagic) + 2 + 2
+	padding := 64 - (preamble+len(header)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+	header += strings.Repeat(" ", padding) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.LittleEndian, t.Data)
+}
+
+// ReadNpy reads a tensor in NumPy `.npy` format. Little-endian int64 and int32
+// arrays in C order are supported.
+func ReadNpy(r io.Reader) (*Tensor, error) {
+	preamble := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, preamble); err != nil {
+		return nil, err
+	}
+	if string(preamble[:len(npyMagic)]) != npyMagic {
+		return nil, errors.New("tensor: not a .npy file")
+	}
+
+	var headerLen int
+	switch major := preamble[len(npyMagic)]; major {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		headerLen = int(n)
+	default:
+		return nil, fmt.Errorf("tensor: unsupported .npy version %d", major)
+	}
+
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	descr := npyDescrRe.FindSubmatch(header)
+	fortran := npyFortranRe.FindSubmatch(header)
+	shapeMatch := npyShapeRe.FindSubmatch(header)
+	if descr == nil || fortran == nil || shapeMatch == nil {
+		return nil, fmt.Errorf("tensor: invalid .npy header %q", header)
+	}
+	if string(fortran[1]) == "True" {
+		return nil, errors.New("tensor: fortran order is not supported")
+	}
+
+	var shape []int
+	for _, d := range strings.Split(string(shapeMatch[1]), ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return nil, fmt.Errorf("tensor: invalid .npy shape %q", shapeMatch[1])
+		}
+		shape = append(shape, n)
+	}
+
+	data, err := readInts(r, string(descr[1]), numElements(shape))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tensor{Shape: shape, Data: data}, nil
+}
+
+// readInts reads n little-endian integers of the given NumPy type.
+func readInts(r io.Reader, descr string, n int) ([]int64, error) {
+	data := make([]int64, n)
+	switch descr {
+	case "<i8":
+		if err := binary.Read(r, binary.LittleEndian, data); err != nil {
+			return nil, err
+		}
+	case "<i4":
+		buf := make([]int32, n)
+		if err := binary.Read(r, binary.LittleEndian, buf); err != nil {
+			return nil, err
+		}
+		for i, v := range buf {
+			data[i] = int64(v)
+		}
+	default:
+		return nil, fmt.Errorf("tensor: unsupported dtype %q", descr)
+	}
+
+	return data, nil
+}
+
+// WriteNpz writes the tensors in NumPy `.npz` format, i.e. a zip archive
+// of `.npy` files named after the tensors.
+func WriteNpz(w io.Writer, tensors map[string]*Tensor) error {
+	names := make([]string, 0, len(tensors))
+	for name := range tensors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := WriteNpy(f, tensors[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ReadNpz reads tensors in NumPy `.npz` format.
+func ReadNpz(r io.ReaderAt, size int64) (map[string]*Tensor, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	tensors := make(map[string]*Tensor, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		t, err := ReadNpy(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("tensor: reading %q: %w", f.Name, err)
+		}
+		tensors[strings.TrimSuffix(f.Name, ".npy")] = t
+	}
+
+	return tensors, nil
+}
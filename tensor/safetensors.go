@@ -0,0 +1,146 @@
+package tensor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// safetensorsMetadataKey is the header key holding free-form string metadata.
+const safetensorsMetadataKey = "__metadata__"
+
+type safetensorsInfo struct {
+	Dtype       string `json:"dtype"`
+	Shape       []int  `json:"shape"`
+	DataOffsets [2]int `json:"data_offsets"`
+}
+
+// WriteSafetensors writes the tensors as I64 tensors in safetensors format,
+// with optional metadata.
+func WriteSafetensors(w io.Writer, tensors map[string]*Tensor, metadata map[string]string) error {
+	names := make([]string, 0, len(tensors))
+	for name := range tensors {
+		if name == safetensorsMetadataKey {
+			return fmt.Errorf("tensor: invalid tensor name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := make(map[string]interface{}, len(tensors)+1)
+	if len(metadata) > 0 {
+		header[safetensorsMetadataKey] = metadata
+	}
+
+	offset := 0
+	for _, name := range names {
+		t := tensors[name]
+		if len(t.Data) != numElements(t.Shape) {
+			return fmt.Errorf("tensor: %q shape %v doesn't match %d elements", name, t.Shape, len(t.Data))
+		}
+		shape := t.Shape
+		if shape == nil {
+			shape = []int{}
+		}
+		size := 8 * len(t.Data)
+		header[name] = safetensorsInfo{Dtype: "I64", Shape: shape, DataOffsets: [2]int{offset, offset + size}}
+		offset += size
+	}
+
+	headerData, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	// Pad the header with spaces so that the data is 8-byte aligned
+	if n := len(headerData) % 8; n != 0 {
+		headerData = append(headerData, bytes.Repeat([]byte(" "), 8-n)...)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(headerData))); err != nil {
+		return err
+	}
+	if _, err := w.Write(headerData); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := binary.Write(w, binary.LittleEndian, tensors[name].Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadSafetensors reads tensors in safetensors format. I64 and I32 tensors are
+// supported.
+func ReadSafetensors(r io.Reader) (tensors map[string]*Tensor, metadata map[string]string, err error) {
+	var headerLen uint64
+	if err := binary.Read(r, binary.LittleEndian, &headerLen); err != nil {
+		return nil, nil, err
+	}
+	// Guard against reading a huge header from a corrupted file
+	const maxHeaderLen = 100 << 20
+	if headerLen > maxHeaderLen {
+		return nil, nil, errors.New("tensor: safetensors header too large")
+	}
+
+	headerData := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, headerData); err != nil {
+		return nil, nil, err
+	}
+
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(headerData, &header); err != nil {
+		return nil, nil, fmt.Errorf("tensor: invalid safetensors header: %w", err)
+	}
+
+	infos := make(map[string]safetensorsInfo, len(header))
+	for name, raw := range header {
+		if name == safetensorsMetadataKey {
+			if err := json.Unmarshal(raw, &metadata); err != nil {
+				return nil, nil, fmt.Errorf("tensor: invalid safetensors metadata: %w", err)
+			}
+			continue
+		}
+		var info safetensorsInfo
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return nil, nil, fmt.Errorf("tensor: invalid safetensors info of %q: %w", name, err)
+		}
+		infos[name] = info
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tensors = make(map[string]*Tensor, len(infos))
+	for name, info := range infos {
+		begin, end := info.DataOffsets[0], info.DataOffsets[1]
+		if begin < 0 || begin > end || end > len(data) {
+			return nil, nil, fmt.Errorf("tensor: invalid data offsets %v of %q", info.DataOffsets, name)
+		}
+
+		var descr string
+		switch info.Dtype {
+		case "I64":
+			descr = "<i8"
+		case "I32":
+			descr = "<i4"
+		default:
+			return nil, nil, fmt.Errorf("tensor: unsupported dtype %q of %q", info.Dtype, name)
+		}
+
+		values, err := readInts(bytes.NewReader(data[begin:end]), descr, numElements(info.Shape))
+		if err != nil {
+			return nil, nil, fmt.Errorf("tensor: reading %q: %w", name, err)
+		}
+		tensors[name] = &Tensor{Shape: info.Shape, Data: values}
+	}
+
+	return tensors, metadata, nil
+}
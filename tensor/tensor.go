@@ -0,0 +1,93 @@
+// Package tensor exports batches of encodings as named int tensors in NumPy
+// (`.npy`, `.npz`) and safetensors formats, and reads them back.
+package tensor
+
+import (
+	"fmt"
+
+	"github.com/sugarme/tokenizer"
+)
+
+// Tensor is a row-major int64 tensor.
+type Tensor struct {
+	Shape []int
+	Data  []int64
+}
+
+// New creates a zero-valued tensor of the given shape.
+func New(shape ...int) *Tensor {
+	return &Tensor{
+		Shape: shape,
+		Data:  make([]int64, numElements(shape)),
+	}
+}
+
+func numElements(shape []int) int {
+	n := 1
+	for _, d := range shape {
+		n *= d
+	}
+
+	return n
+}
+
+// Names of the tensors created by FromEncodings, following the `transformers` naming.
+const (
+	InputIds          = "input_ids"
+	AttentionMask     = "attention_mask"
+	TokenTypeIds      = "token_type_ids"
+	SpecialTokensMask = "special_tokens_mask"
+	OffsetMapping     = "offset_mapping"
+)
+
+// FromEncodings converts a batch of encodings into named tensors of shape
+// [batch, seqLen] (and [batch, seqLen, 2] for offsets).
+//
+// All encodings must have the same length, i.e. be padded. Outputs that have
+// been stripped (e.g. with `tokenizer.WithReturnOffsets(false)`) are left out.
+func FromEncodings(encodings []tokenizer.Encoding) (map[string]*Tensor, error) {
+	batch, err := tokenizer.NewBatchEncoding(encodings)
+	if err != nil {
+		return nil, err
+	}
+
+	b, l := batch.BatchSize, batch.SeqLen
+	tensors := map[string]*Tensor{
+		InputIds:     New(b, l),
+		TokenTypeIds: New(b, l),
+	}
+
+	var hasMasks, hasOffsets bool = b > 0, b > 0
+	for _, e := range encodings {
+		hasMasks = hasMasks && len(e.AttentionMask) == l && len(e.SpecialTokenMask) == l
+		hasOffsets = hasOffsets && len(e.Offsets) == l
+	}
+	if hasMasks {
+		tensors[AttentionMask] = New(b, l)
+		tensors[SpecialTokensMask] = New(b, l)
+	}
+	if hasOffsets {
+		tensors[OffsetMapping] = New(b, l, 2)
+	}
+
+	for i, e := range encodings {
+		for j := 0; j < l; j++ {
+			k := i*l + j
+			tensors[InputIds].Data[k] = int64(e.Ids[j])
+			tensors[TokenTypeIds].Data[k] = int64(e.TypeIds[j])
+			if hasMasks {
+				tensors[AttentionMask].Data[k] = int64(e.AttentionMask[j])
+				tensors[SpecialTokensMask].Data[k] = int64(e.SpecialTokenMask[j])
+			}
+			if hasOffsets {
+				if len(e.Offsets[j]) != 2 {
+					return nil, fmt.Errorf("tensor: invalid offsets %v of token %d in encoding %d", e.Offsets[j], j, i)
+				}
+				tensors[OffsetMapping].Data[2*k] = int64(e.Offsets[j][0])
+				tensors[OffsetMapping].Data[2*k+1] = int64(e.Offsets[j][1])
+			}
+		}
+	}
+
+	return tensors, nil
+}
@@ -0,0 +1,119 @@
+package tensor_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+	"github.com/sugarme/tokenizer/tensor"
+)
+
+func encodeBatch(t *testing.T) []tokenizer.Encoding {
+	tk := pretrained.BertBaseUncased()
+	tk.WithPadding(&tokenizer.PaddingParams{
+		Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithBatchLongest()),
+		Direction: tokenizer.Right,
+		PadToken:  "[PAD]",
+	})
+
+	inputs := []tokenizer.EncodeInput{
+		tokenizer.NewSingleEncodeInput(tokenizer.NewInputSequence("hello")),
+		tokenizer.NewSingleEncodeInput(tokenizer.NewInputSequence("hello world")),
+	}
+	encodings, err := tk.EncodeBatch(inputs, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return encodings
+}
+
+func TestFromEncodings(t *testing.T) {
+	tensors, err := tensor.FromEncodings(encodeBatch(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*tensor.Tensor{
+		tensor.InputIds:          {Shape: []int{2, 4}, Data: []int64{101, 7592, 102, 0, 101, 7592, 2088, 102}},
+		tensor.AttentionMask:     {Shape: []int{2, 4}, Data: []int64{1, 1, 1, 0, 1, 1, 1, 1}},
+		tensor.TokenTypeIds:      {Shape: []int{2, 4}, Data: []int64{0, 0, 0, 0, 0, 0, 0, 0}},
+		tensor.SpecialTokensMask: {Shape: []int{2, 4}, Data: []int64{1, 0, 1, 1, 1, 0, 0, 1}},
+		tensor.OffsetMapping:     {Shape: []int{2, 4, 2}, Data: []int64{0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 5, 6, 11, 0, 0}},
+	}
+	if !reflect.DeepEqual(want, tensors) {
+		for name, w := range want {
+			t.Errorf("%s: want %v, got %v", name, w, tensors[name])
+		}
+	}
+}
+
+func TestNpy(t *testing.T) {
+	want := &tensor.Tensor{Shape: []int{2, 3}, Data: []int64{1, 2, 3, -4, 5, 6}}
+
+	var buf bytes.Buffer
+	if err := tensor.WriteNpy(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	header := "\x93NUMPY\x01\x00v\x00{'descr': '<i8', 'fortran_order': False, 'shape': (2, 3), }"
+	if !strings.HasPrefix(string(data), header) || (len(data)-48)%64 != 0 {
+		t.Errorf("invalid .npy header: %q", data[:len(data)-48])
+	}
+
+	got, err := tensor.ReadNpy(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestNpz(t *testing.T) {
+	want, err := tensor.FromEncodings(encodeBatch(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tensor.WriteNpz(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tensor.ReadNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestSafetensors(t *testing.T) {
+	want, err := tensor.FromEncodings(encodeBatch(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMetadata := map[string]string{"tokenizer": "bert-base-uncased"}
+
+	var buf bytes.Buffer
+	if err := tensor.WriteSafetensors(&buf, want, wantMetadata); err != nil {
+		t.Fatal(err)
+	}
+
+	got, metadata, err := tensor.ReadSafetensors(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if !reflect.DeepEqual(wantMetadata, metadata) {
+		t.Errorf("want metadata %v, got %v", wantMetadata, metadata)
+	}
+}
@@ -3,15 +3,18 @@ package tokenizer
 
 import (
 	"bufio"
-	// "context"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"os"
+	"runtime"
 	"strings"
 
 	// "regexp"
 	"sync"
+	"sync/atomic"
 
 	progressbar "github.com/schollz/progressbar/v2"
 	"golang.org/x/sync/errgroup"
@@ -72,6 +75,16 @@ type PostProcessor interface {
 	Process(encoding, pairEncoding *Encoding, addSpecialTokens bool) *Encoding
 }
 
+// MultiPostProcessor is a PostProcessor that can also process inputs of more
+// than two sequences (see `NewMultiEncodeInput`).
+type MultiPostProcessor interface {
+	PostProcessor
+	// AddedTokensMulti returns the number of tokens that will be added when processing `n` sequences
+	AddedTokensMulti(n int) int
+	// ProcessMulti processes all encodings and returns a new merged one
+	ProcessMulti(encodings []*Encoding, addSpecialTokens bool) (*Encoding, error)
+}
+
 // DefaultProcess is a helper function of PostProcessor's Process method
 // It helps to fast track by just merging encoding and its pair.
 func DefaultProcess(encoding, pairEncoding *Encoding, addSpecialTokens bool) *Encoding {
@@ -80,17 +93,45 @@ func DefaultProcess(encoding, pairEncoding *Encoding, addSpecialTokens bool) *En
 	}
 
 	if pairEncoding != nil {
+		encoding.setSequenceIds(0)
+		pairEncoding.setSequenceIds(1)
 		return encoding.MergeWith(pairEncoding, false)
 	}
 
 	return encoding
 }
 
+// DefaultProcessMulti merges the given encodings, setting their sequence ids.
+func DefaultProcessMulti(encodings []*Encoding) *Encoding {
+	var merged *Encoding
+	for i, encoding := range encodings {
+		encoding.setSequenceIds(i)
+		if merged == nil {
+			merged = encoding
+			continue
+		}
+		merged = merged.MergeWith(encoding, false)
+	}
+
+	return merged
+}
+
 // PrepareEncodings prepares encoding and pairEncoding if any before `ProcessEncodings` call.
 func PrepareEncodings(encoding, pairEncoding *Encoding) (out []Encoding) {
-	encodings := []Encoding{*encoding}
+	encodings := []*Encoding{encoding}
 	if pairEncoding != nil {
-		encodings = append(encodings, *pairEncoding)
+		encodings = append(encodings, pairEncoding)
+	}
+
+	return PrepareMultiEncodings(encodings)
+}
+
+// PrepareMultiEncodings prepares encodings before `ProcessEncodings` call: it sets
+// the sequence ids and the type ids of each encoding to its index.
+func PrepareMultiEncodings(in []*Encoding) (out []Encoding) {
+	encodings := make([]Encoding, len(in))
+	for i, e := range in {
+		encodings[i] = *e
 	}
 	for i, encoding := range encodings {
 		encoding.SetSequenceIds(i)
@@ -240,6 +281,13 @@ type Dual struct {
 
 func (Dual) private() {}
 
+// Multi is an input of any number of sequences, e.g. (query, title, body).
+type Multi struct {
+	Sentences []InputSequence
+}
+
+func (Multi) private() {}
+
 type EncodeInput interface {
 	private()
 }
@@ -252,9 +300,36 @@ func NewDualEncodeInput(sentence, pairSentence InputSequence) (retVal EncodeInpu
 	return Dual{sentence, pairSentence}
 }
 
+// NewMultiEncodeInput creates an input of the given sequences. Inputs of more
+// than two sequences require a post-processor implementing `MultiPostProcessor`
+// (e.g. `TemplateProcessing` with a template for that number of sequences) or
+// no post-processor.
+func NewMultiEncodeInput(sentences ...InputSequence) (retVal EncodeInput) {
+	return Multi{sentences}
+}
+
 // Tokenizer represents a tokenization pipeline.
 // It can implement any encoding or decoding of any text.
+//
+// A Tokenizer is safe for concurrent use. Its configuration (components, added
+// vocabulary, truncation and padding params) is an immutable snapshot: every
+// call works on the snapshot that is current when it starts, and the methods
+// modifying the tokenizer (`With*`, `AddTokens`, `Train`...) replace it with a
+// modified copy (copy-on-write). So modifications neither race with nor block
+// encoding and decoding. Use `Update` to apply several modifications at once,
+// and `Clone` to get an independent copy.
+//
+// Components are shared by snapshots and clones: they must not be modified
+// once set, set a new one instead. The same goes for the params returned by
+// `GetTruncation` and `GetPadding`.
 type Tokenizer struct {
+	mu     sync.Mutex // serializes modifications
+	config atomic.Pointer[tokenizerConfig]
+}
+
+// tokenizerConfig is a snapshot of the configuration of a Tokenizer. It's
+// never modified once stored in a Tokenizer.
+type tokenizerConfig struct {
 	// Parts
 	normalizer    normalizer.Normalizer // optional
 	preTokenizer  PreTokenizer          // optional
@@ -268,83 +343,230 @@ type Tokenizer struct {
 	// General processing parameters
 	trunc   *TruncationParams // optional
 	padding *PaddingParams    // optional
+
+	// Special token roles set with `WithSpecialToken`
+	specialTokenRoles map[SpecialTokenRole]string
+	// Special token roles resolved from the set ones and the components
+	resolvedRoles map[SpecialTokenRole]string
 }
 
 // Implementing methods for Tokenizer
 func NewTokenizer(model Model) *Tokenizer {
-	return &Tokenizer{
-		normalizer:      nil,
-		preTokenizer:    nil,
-		model:           model,
-		postProcessor:   nil,
-		decoder:         nil,
-		addedVocabulary: NewAddedVocabulary(),
-		trunc:           nil,
-		padding:         nil,
+	t := new(Tokenizer)
+	c := &tokenizerConfig{
+		normalizer:        nil,
+		preTokenizer:      nil,
+		model:             model,
+		postProcessor:     nil,
+		decoder:           nil,
+		addedVocabulary:   NewAddedVocabulary(),
+		trunc:             nil,
+		padding:           nil,
+		specialTokenRoles: make(map[SpecialTokenRole]string),
+	}
+	c.resolveSpecialTokenRoles()
+	t.config.Store(c)
+
+	return t
+}
+
+// load returns the current configuration snapshot.
+func (t *Tokenizer) load() *tokenizerConfig {
+	return t.config.Load()
+}
+
+// update replaces the configuration with a copy modified by fn.
+func (t *Tokenizer) update(fn func(c *tokenizerConfig)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c := t.load().clone()
+	fn(c)
+	c.resolveSpecialTokenRoles()
+	t.config.Store(c)
+}
+
+// clone returns a copy of the configuration that can be modified. Components
+// are shared.
+func (c *tokenizerConfig) clone() *tokenizerConfig {
+	clone := *c
+	clone.addedVocabulary = c.addedVocabulary.Clone()
+	clone.specialTokenRoles = make(map[SpecialTokenRole]string, len(c.specialTokenRoles))
+	for role, token := range c.specialTokenRoles {
+		clone.specialTokenRoles[role] = token
+	}
+
+	return &clone
+}
+
+// Cloner is implemented by components that can be deep-copied by
+// `Tokenizer.Clone`, e.g. because they hold a cache or can be modified in
+// place. T is the component interface, e.g. `Cloner[Model]`.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// CloneComponent returns a deep copy of the component if it implements
+// `Cloner[T]`, the component itself otherwise.
+func CloneComponent[T any](v T) T {
+	if c, ok := any(v).(Cloner[T]); ok {
+		return c.Clone()
+	}
+
+	return v
+}
+
+// Clone returns a deep copy of the tokenizer, that can be modified without
+// affecting t.
+//
+// Components implementing `Cloner` (pointers to the models, `TemplateProcessing`
+// and the `Sequence` containers) are deep-copied; the other components, which
+// are expected not to change once set, are shared with t.
+func (t *Tokenizer) Clone() *Tokenizer {
+	c := t.load().clone()
+	c.normalizer = CloneComponent(c.normalizer)
+	c.preTokenizer = CloneComponent(c.preTokenizer)
+	c.model = CloneComponent(c.model)
+	c.postProcessor = CloneComponent(c.postProcessor)
+	c.decoder = CloneComponent(c.decoder)
+
+	clone := new(Tokenizer)
+	clone.config.Store(c)
+
+	return clone
+}
+
+// Update applies several modifications at once: fn modifies a copy of the
+// tokenizer, which then replaces the configuration of t, so that concurrent
+// calls see either none or all of the modifications. If fn returns an error,
+// t is left unchanged.
+//
+// Modifications of t are blocked until fn returns, so fn must not modify t itself.
+//
+// Example:
+//
+//	err := tk.Update(func(tk *tokenizer.Tokenizer) error {
+//		tk.AddSpecialTokens(tokens)
+//		tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 512})
+//		return nil
+//	})
+func (t *Tokenizer) Update(fn func(tk *Tokenizer) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clone := t.Clone()
+	if err := fn(clone); err != nil {
+		return err
 	}
+	t.config.Store(clone.load())
+
+	return nil
 }
 
 func (t *Tokenizer) WithNormalizer(n normalizer.Normalizer) {
-	t.normalizer = n
+	t.update(func(c *tokenizerConfig) {
+		c.normalizer = n
+	})
 }
 
 func (t *Tokenizer) GetNormalizer() normalizer.Normalizer {
-	return t.normalizer
+	return t.load().normalizer
 }
 
 func (t *Tokenizer) WithPreTokenizer(preTokenizer PreTokenizer) {
-	t.preTokenizer = preTokenizer
+	t.update(func(c *tokenizerConfig) {
+		c.preTokenizer = preTokenizer
+	})
 }
 
 func (t *Tokenizer) GetPreTokenizer() PreTokenizer {
-	return t.preTokenizer
+	return t.load().preTokenizer
 }
 
 func (t *Tokenizer) WithPostProcessor(postProcessor PostProcessor) {
-	t.postProcessor = postProcessor
+	t.update(func(c *tokenizerConfig) {
+		c.postProcessor = postProcessor
+	})
 }
 
 func (t *Tokenizer) GetPostProcessor() PostProcessor {
-	return t.postProcessor
+	return t.load().postProcessor
 }
 
 func (t *Tokenizer) WithDecoder(decoder Decoder) {
-	t.decoder = decoder
+	t.update(func(c *tokenizerConfig) {
+		c.decoder = decoder
+	})
 }
 
 func (t *Tokenizer) GetDecoder() Decoder {
-	return t.decoder
+	return t.load().decoder
 }
 
 func (t *Tokenizer) WithModel(model Model) {
-	t.model = model
+	t.update(func(c *tokenizerConfig) {
+		c.model = model
+	})
 }
 
 func (t *Tokenizer) GetModel() Model {
-	return t.model
+	return t.load().model
 }
 
+// WithTruncation sets the truncation params. Nil disables truncation.
+// The params are copied.
 func (t *Tokenizer) WithTruncation(trunc *TruncationParams) {
-	t.trunc = trunc
+	if trunc != nil {
+		params := *trunc
+		trunc = &params
+	}
+	t.update(func(c *tokenizerConfig) {
+		c.trunc = trunc
+	})
 }
 
 func (t *Tokenizer) GetTruncation() *TruncationParams {
-	return t.trunc
+	return t.load().trunc
 }
 
+// WithPadding sets the padding params. Nil disables padding.
+// The params are copied.
 func (t *Tokenizer) WithPadding(padding *PaddingParams) {
-	t.padding = padding
+	if padding != nil {
+		params := *padding
+		padding = &params
+	}
+	t.update(func(c *tokenizerConfig) {
+		c.padding = padding
+	})
 }
 
 func (t *Tokenizer) GetPadding() (retVal *PaddingParams) {
-	return t.padding
+	return t.load().padding
 }
 
-// GetVocab get the vocabulary
+// GetVocab get the vocabulary. The returned map is a copy.
 func (t *Tokenizer) GetVocab(withAddedTokens bool) map[string]int {
-	finalVocab := t.model.GetVocab()
+	c := t.load()
+	modelVocab := c.model.GetVocab()
+	finalVocab := make(map[string]int, len(modelVocab))
+	for k, v := range modelVocab {
+		finalVocab[k] = v
+	}
 	if withAddedTokens {
-		addedVocab := t.addedVocabulary.GetVocab()
+		// Model tokens whose id is assigned to an added token are replaced,
+		// and the ones given another id leave their model id
+		for id, tok := range c.addedVocabulary.addedTokenMapR {
+			if modelTok, ok := c.model.IdToToken(id); ok && modelTok != tok {
+				delete(finalVocab, modelTok)
+			}
+		}
+		for _, id := range c.addedVocabulary.VacatedIds() {
+			if modelTok, ok := c.model.IdToToken(id); ok {
+				delete(finalVocab, modelTok)
+			}
+		}
+		addedVocab := c.addedVocabulary.GetVocab()
 		if len(addedVocab) > 0 {
 			for k, v := range addedVocab {
 				finalVocab[k] = v
@@ -357,17 +579,26 @@ func (t *Tokenizer) GetVocab(withAddedTokens bool) map[string]int {
 
 // GetVocabSize get the size of vocabulary
 func (t *Tokenizer) GetVocabSize(withAddedTokens bool) int {
+	c := t.load()
 	if !withAddedTokens {
-		return t.model.GetVocabSize()
+		return c.model.GetVocabSize()
+	}
+
+	// Added tokens may replace model tokens, or move them to another id
+	size := c.model.GetVocabSize()
+	for _, id := range c.addedVocabulary.GetVocab() {
+		if _, ok := c.model.IdToToken(id); !ok {
+			size++
+		}
 	}
 
-	return t.model.GetVocabSize() + t.addedVocabulary.Len()
+	return size - len(c.addedVocabulary.VacatedIds())
 }
 
 // GetSpecialTokens returns a slice of special tokens.
 func (t *Tokenizer) GetSpecialTokens() []string {
 	var tokens []string
-	for k := range t.addedVocabulary.specialTokensSet {
+	for k := range t.load().addedVocabulary.specialTokensSet {
 		tokens = append(tokens, k)
 	}
 
@@ -376,27 +607,35 @@ func (t *Tokenizer) GetSpecialTokens() []string {
 
 // TokenToId converts a token to a corresponding id
 func (t *Tokenizer) TokenToId(token string) (id int, ok bool) {
-	id, ok = t.addedVocabulary.TokenToId(token, t.model)
+	c := t.load()
+	id, ok = c.addedVocabulary.TokenToId(token, c.model)
 	return id, ok
 }
 
 // IdToToken converts an Id to a corresponding token
 func (t *Tokenizer) IdToToken(id int) (token string, ok bool) {
-	token, ok = t.addedVocabulary.IdToToken(id, t.model)
+	c := t.load()
+	token, ok = c.addedVocabulary.IdToToken(id, c.model)
 	return token, ok
 }
 
 // EncodeSingleSequence encodes a single sequence
 func (t *Tokenizer) EncodeSingleSequence(sequence InputSequence, typeId int, offsetType OffsetType) (*Encoding, error) {
+	return t.load().encodeSingleSequence(sequence, typeId, offsetType, nil)
+}
+
+// encodeSingleSequence encodes a single sequence, extracting the special tokens
+// kept by the optional filter.
+func (c *tokenizerConfig) encodeSingleSequence(sequence InputSequence, typeId int, offsetType OffsetType, filter specialFilter) (*Encoding, error) {
 	encode := func(isPreTokenized bool, subseqIdx int, subseq string) (*Encoding, error) {
-		normalized := t.addedVocabulary.ExtractAndNormalize(subseq, t.normalizer)
-		var (
-			pretokenized *PreTokenizedString = normalized
-			err          error
-		)
-
-		if t.preTokenizer != nil {
-			pretokenized, err = t.doPreTokenize(normalized)
+		normalized, err := c.addedVocabulary.extractAndNormalize(subseq, c.normalizer, filter)
+		if err != nil {
+			return nil, err
+		}
+		var pretokenized *PreTokenizedString = normalized
+
+		if c.preTokenizer != nil {
+			pretokenized, err = c.doPreTokenize(normalized)
 			if err != nil {
 				return nil, err
 			}
@@ -412,7 +651,7 @@ func (t *Tokenizer) EncodeSingleSequence(sequence InputSequence, typeId int, off
 			wordIdx = subseqIdx
 		}
 
-		subseqEncoding, err := t.doTokenize(pretokenized, typeId, wordIdx, offsetType)
+		subseqEncoding, err := c.doTokenize(pretokenized, typeId, wordIdx, offsetType)
 
 		// fmt.Printf("==========doTokenizer result: =====================\n")
 		// fmt.Printf("encoding: %+v\n", subseqEncoding)
@@ -450,37 +689,32 @@ func (t *Tokenizer) EncodeSingleSequence(sequence InputSequence, typeId int, off
 // Encode the given input. This method accepts both single sequences, as well as pair
 // sequences. Also, a sequence can be a string, or already pre-tokenized input directly:
 func (t *Tokenizer) Encode(input EncodeInput, addSpecialTokens bool) (retVal *Encoding, err error) {
-	var encoding, pairEncoding *Encoding
-
-	// Encode and Postprocess
-	switch v := input.(type) {
-	case Single:
-		encoding, err = t.EncodeSingleSequence(v.Sentence, 0, Byte)
-		if err != nil {
-			return retVal, err
-		}
-
-	case Dual:
-		encoding, err = t.EncodeSingleSequence(v.Sentence, 0, Byte)
-		if err != nil {
-			return retVal, err
-		}
-		pairEncoding, err = t.EncodeSingleSequence(v.Pair, 1, Byte)
-		if err != nil {
-			return retVal, err
-		}
-
-	default:
-		log.Fatalf("Invalid input type - '%T'. \n", input)
-	}
-
-	return t.PostProcess(encoding, pairEncoding, addSpecialTokens), nil
+	return t.EncodeWithOptions(input, WithAddSpecialTokens(addSpecialTokens))
 }
 
 // EncodeCharOffsets encodes the given input, using offsets relative to chars instead of bytes.
 // This method accepts both single sequences, as well as pair sequences. Also,
 // a sequence can be a string, or already pre-tokenized input directly:
 func (t *Tokenizer) EncodeCharOffsets(input EncodeInput, addSpecialTokens bool) (*Encoding, error) {
+	return t.EncodeWithOptions(input, WithAddSpecialTokens(addSpecialTokens), WithOffsetType(Char))
+}
+
+// EncodeWithOptions encodes the given input using the tokenizer configuration
+// overridden by the given options. The tokenizer itself is not modified.
+func (t *Tokenizer) EncodeWithOptions(input EncodeInput, opts ...EncodeOption) (*Encoding, error) {
+	c := t.load()
+	o := c.newEncodeOptions(opts...)
+	encoding, err := c.encode(input, o)
+	if err != nil {
+		return nil, err
+	}
+	o.strip(encoding)
+
+	return encoding, nil
+}
+
+// encode encodes and post-processes the given input with resolved options.
+func (c *tokenizerConfig) encode(input EncodeInput, o *EncodeOptions) (*Encoding, error) {
 	var (
 		encoding, pairEncoding *Encoding
 		err                    error
@@ -489,41 +723,187 @@ func (t *Tokenizer) EncodeCharOffsets(input EncodeInput, addSpecialTokens bool)
 	// Encode and Postprocess
 	switch v := input.(type) {
 	case Single:
-		encoding, err = t.EncodeSingleSequence(v.Sentence, 0, Char)
+		encoding, err = c.encodeSingleSequence(v.Sentence, 0, o.offsetType(), o.specialFilter())
 		if err != nil {
 			return nil, err
 		}
 
 	case Dual:
-		encoding, err = t.EncodeSingleSequence(v.Sentence, 0, Char)
+		encoding, err = c.encodeSingleSequence(v.Sentence, 0, o.offsetType(), o.specialFilter())
 		if err != nil {
 			return nil, err
 		}
-		pairEncoding, err = t.EncodeSingleSequence(v.Pair, 1, Char)
+		pairEncoding, err = c.encodeSingleSequence(v.Pair, 1, o.offsetType(), o.specialFilter())
 		if err != nil {
 			return nil, err
 		}
 
+	case Multi:
+		if len(v.Sentences) == 0 {
+			return nil, errors.New("Invalid input: no sequence")
+		}
+		encodings := make([]*Encoding, len(v.Sentences))
+		for i, sentence := range v.Sentences {
+			encodings[i], err = c.encodeSingleSequence(sentence, i, o.offsetType(), o.specialFilter())
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(encodings) > 2 {
+			finalEncoding, err := c.postProcessMulti(encodings, o.AddSpecialTokens, o.Truncation, o.Padding)
+			if err != nil {
+				return nil, err
+			}
+			finalEncoding.setOffsetType(o.offsetType())
+			return finalEncoding, nil
+		}
+		encoding = encodings[0]
+		if len(encodings) == 2 {
+			pairEncoding = encodings[1]
+		}
+
 	default:
 		log.Fatalf("Invalid input type - '%T'. \n", input)
 	}
 
-	return t.PostProcess(encoding, pairEncoding, addSpecialTokens), nil
+	finalEncoding, err := c.postProcess(encoding, pairEncoding, o.AddSpecialTokens, o.Truncation, o.Padding)
+	if err != nil {
+		return nil, err
+	}
+	finalEncoding.setOffsetType(o.offsetType())
+
+	return finalEncoding, nil
 }
 
 // Decode decodes the given ids, back to a String
+//
+// It is `DecodeWithOptions` with the default `SkipUnknownIds` policy: ids
+// unknown to the vocabulary are dropped silently. Use `DecodeWithOptions` and
+// `WithUnknownIdPolicy` to report or replace them instead.
 func (t *Tokenizer) Decode(ids []int, skipSpecialTokens bool) (retVal string) {
-	var tokens []string
-	for _, id := range ids {
-		if tok, ok := t.addedVocabulary.IdToToken(id, t.model); ok {
-			if !skipSpecialTokens || !t.addedVocabulary.IsSpecialToken(tok) {
-				tokens = append(tokens, tok)
+	retVal, _ = t.DecodeWithOptions(ids, WithSkipSpecialTokens(skipSpecialTokens))
+	return retVal
+}
+
+// UnknownIdPolicy specifies how decoding handles ids that are not in the vocabulary.
+type UnknownIdPolicy int
+
+const (
+	// SkipUnknownIds drops unknown ids silently.
+	SkipUnknownIds UnknownIdPolicy = iota
+	// ErrorOnUnknownIds returns an `ErrUnknownId` error.
+	ErrorOnUnknownIds
+	// ReplaceUnknownIds replaces unknown ids with `DecodeOptions.Replacement`.
+	ReplaceUnknownIds
+)
+
+// ErrUnknownId is returned when decoding an id that is not in the vocabulary.
+var ErrUnknownId = errors.New("unknown token id")
+
+// DecodeOptions holds the options of decoding calls.
+type DecodeOptions struct {
+	// Whether to remove special tokens
+	SkipSpecialTokens bool
+	// How to handle ids unknown to the vocabulary
+	UnknownIds UnknownIdPolicy
+	// Token used in place of unknown ids with `ReplaceUnknownIds` policy
+	Replacement string
+	// Maximum number of sequences decoded at the same time by batch decoding.
+	// Zero or negative value means `runtime.GOMAXPROCS(0)`.
+	Concurrency int
+}
+
+type DecodeOption func(o *DecodeOptions)
+
+// WithSkipSpecialTokens specifies whether to remove special tokens.
+func WithSkipSpecialTokens(v bool) DecodeOption {
+	return func(o *DecodeOptions) {
+		o.SkipSpecialTokens = v
+	}
+}
+
+// WithUnknownIdPolicy specifies how to handle ids unknown to the vocabulary.
+func WithUnknownIdPolicy(v UnknownIdPolicy) DecodeOption {
+	return func(o *DecodeOptions) {
+		o.UnknownIds = v
+	}
+}
+
+// WithReplacement sets the token used in place of unknown ids. It implies
+// `ReplaceUnknownIds` policy.
+func WithReplacement(v string) DecodeOption {
+	return func(o *DecodeOptions) {
+		o.UnknownIds = ReplaceUnknownIds
+		o.Replacement = v
+	}
+}
+
+// WithDecodeConcurrency sets the maximum number of sequences decoded at the same time.
+func WithDecodeConcurrency(n int) DecodeOption {
+	return func(o *DecodeOptions) {
+		o.Concurrency = n
+	}
+}
+
+func DefaultDecodeOptions() *DecodeOptions {
+	return &DecodeOptions{
+		SkipSpecialTokens: false,
+		UnknownIds:        SkipUnknownIds,
+		Replacement:       "\uFFFD",
+		Concurrency:       runtime.GOMAXPROCS(0),
+	}
+}
+
+func newDecodeOptions(opts ...DecodeOption) *DecodeOptions {
+	o := DefaultDecodeOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	return o
+}
+
+// idsToTokens converts ids to tokens following the given options.
+func (c *tokenizerConfig) idsToTokens(ids []int, o *DecodeOptions) ([]string, error) {
+	tokens := make([]string, 0, len(ids))
+	for i, id := range ids {
+		tok, ok := c.addedVocabulary.IdToToken(id, c.model)
+		if !ok {
+			switch o.UnknownIds {
+			case ErrorOnUnknownIds:
+				return nil, fmt.Errorf("%w: %d at position %d", ErrUnknownId, id, i)
+			case ReplaceUnknownIds:
+				tokens = append(tokens, o.Replacement)
 			}
+			continue
+		}
+		if !o.SkipSpecialTokens || !(c.addedVocabulary.IsSpecialToken(tok) || c.isRoleToken(tok)) {
+			tokens = append(tokens, tok)
 		}
 	}
 
-	if t.decoder != nil {
-		return (t.decoder).Decode(tokens)
+	return tokens, nil
+}
+
+// DecodeWithOptions decodes the given ids, back to a String.
+func (t *Tokenizer) DecodeWithOptions(ids []int, opts ...DecodeOption) (string, error) {
+	c := t.load()
+	o := newDecodeOptions(opts...)
+	tokens, err := c.idsToTokens(ids, o)
+	if err != nil {
+		return "", err
+	}
+
+	return c.decodeTokens(tokens), nil
+}
+
+// decodeTokens merges tokens to string, handling the case where there is no Decoder set.
+func (c *tokenizerConfig) decodeTokens(tokens []string) string {
+	if c.decoder != nil {
+		return (c.decoder).Decode(tokens)
 	}
 
 	return strings.Join(tokens, " ")
@@ -532,19 +912,68 @@ func (t *Tokenizer) Decode(ids []int, skipSpecialTokens bool) (retVal string) {
 // AddSpecialTokens registers the given tokens as special tokens. This is especially useful for removing
 // these special tokens while decoding
 func (t *Tokenizer) AddSpecialTokens(tokens []AddedToken) (retVal int) {
-	return t.addedVocabulary.AddSpecialTokens(tokens, t.model, t.normalizer)
+	t.update(func(c *tokenizerConfig) {
+		retVal = c.addedVocabulary.AddSpecialTokens(tokens, c.model, c.normalizer)
+	})
+	return retVal
 }
 
 // AddTokens adds the given tokens to the added vocabulary
 func (t *Tokenizer) AddTokens(tokens []AddedToken) (retVal int) {
-	return t.addedVocabulary.AddTokens(tokens, t.model, t.normalizer)
+	t.update(func(c *tokenizerConfig) {
+		retVal = c.addedVocabulary.AddTokens(tokens, c.model, c.normalizer)
+	})
+	return retVal
+}
+
+// AddTokensWithIds adds the given tokens to the added vocabulary with the given ids,
+// replacing the tokens previously at these ids. See `AddedVocabulary.AddTokensWithIds`.
+func (t *Tokenizer) AddTokensWithIds(tokens []AddedTokenWithId) (err error) {
+	t.update(func(c *tokenizerConfig) {
+		err = c.addedVocabulary.AddTokensWithIds(tokens, c.model, c.normalizer)
+	})
+	return err
+}
+
+// AddTokenPatterns adds the families of tokens defined by the given patterns to
+// the added vocabulary. See `AddedVocabulary.AddTokenPatterns`.
+func (t *Tokenizer) AddTokenPatterns(patterns []AddedTokenPattern) (err error) {
+	t.update(func(c *tokenizerConfig) {
+		err = c.addedVocabulary.AddTokenPatterns(patterns, c.model, c.normalizer)
+	})
+	return err
+}
+
+// GetAddedTokens returns all the added tokens with their ids, sorted by id.
+// Tokens of patterns are listed individually.
+func (t *Tokenizer) GetAddedTokens() []AddedTokenWithId {
+	c := t.load()
+	return c.addedVocabulary.AddedTokens(c.model)
+}
+
+// RemoveTokens removes the given tokens from the added vocabulary. It returns
+// the number of removed tokens.
+func (t *Tokenizer) RemoveTokens(tokens []string) (retVal int) {
+	t.update(func(c *tokenizerConfig) {
+		retVal = c.addedVocabulary.RemoveTokens(tokens, c.model, c.normalizer)
+	})
+	return retVal
+}
+
+// UpdateAddedToken replaces the options of the added token with the same
+// content, and sets whether it is special.
+func (t *Tokenizer) UpdateAddedToken(token AddedToken, special bool) (err error) {
+	t.update(func(c *tokenizerConfig) {
+		err = c.addedVocabulary.UpdateToken(token, special, c.model, c.normalizer)
+	})
+	return err
 }
 
 // doNormalize does Normalization logic, go through all normalizers
-func (t *Tokenizer) doNormalize(s string) (retVal *normalizer.NormalizedString, err error) {
+func (c *tokenizerConfig) doNormalize(s string) (retVal *normalizer.NormalizedString, err error) {
 	normalized := normalizer.NewNormalizedFrom(s)
-	if t.normalizer != nil {
-		normalized, err = (t.normalizer).Normalize(normalized)
+	if c.normalizer != nil {
+		normalized, err = (c.normalizer).Normalize(normalized)
 		if err != nil {
 			return retVal, err
 		}
@@ -554,23 +983,23 @@ func (t *Tokenizer) doNormalize(s string) (retVal *normalizer.NormalizedString,
 }
 
 // doPreTokenize does the pretokenization logic, handling the case where there is no PreTokenizer set
-func (t *Tokenizer) doPreTokenize(pretokenized *PreTokenizedString) (*PreTokenizedString, error) {
-	if t.preTokenizer == nil {
+func (c *tokenizerConfig) doPreTokenize(pretokenized *PreTokenizedString) (*PreTokenizedString, error) {
+	if c.preTokenizer == nil {
 		err := fmt.Errorf("Tokenizer.doPreTokenize() failed: there's no 'PreTokenizer' setup. You have to include a 'PreTokenizer' at the time of creating 'Tokenizer'.")
 		return nil, err
 	}
-	return (t.preTokenizer).PreTokenize(pretokenized)
+	return (c.preTokenizer).PreTokenize(pretokenized)
 }
 
 // doTokenize does Tokenization logic, makes the bridge between the pre-tokenization phase and the real
 // tokenization phase, and converting offsets back to the original referential.
-func (t *Tokenizer) doTokenize(pretokenized *PreTokenizedString, typeId int, wordIdx int, offsetType OffsetType) (*Encoding, error) {
+func (c *tokenizerConfig) doTokenize(pretokenized *PreTokenizedString, typeId int, wordIdx int, offsetType OffsetType) (*Encoding, error) {
 	pretok, err := pretokenized.Tokenize(func(normalized *normalizer.NormalizedString) ([]Token, error) {
-		if t.model == nil {
+		if c.model == nil {
 			err := fmt.Errorf("Tokenizer.doTokenize() failed: there's no 'Tokenizer Model' setup. You have to include a 'Tokenizer Model' at the time of creating 'Tokenizer'.")
 			return nil, err
 		}
-		return (t.model).Tokenize(normalized.GetNormalized())
+		return (c.model).Tokenize(normalized.GetNormalized())
 	})
 	if err != nil {
 		return nil, err
@@ -585,113 +1014,406 @@ func (t *Tokenizer) doTokenize(pretokenized *PreTokenizedString, typeId int, wor
 }
 
 // PostProcess does post-processing logic, handling the case where there is no PostProcessor set
+//
+// NOTE. It exits on truncation errors; the `Encode*` methods return them.
 func (t *Tokenizer) PostProcess(encoding, pairEncoding *Encoding, addSpecialTokens bool) (retVal *Encoding) {
-	var tEncoding, tPairEncoding *Encoding
+	c := t.load()
+	o := c.newEncodeOptions()
+	retVal, err := c.postProcess(encoding, pairEncoding, addSpecialTokens, o.Truncation, o.Padding)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return retVal
+}
+
+// postProcess does post-processing logic with the given (optional) truncation and padding params.
+func (c *tokenizerConfig) postProcess(encoding, pairEncoding *Encoding, addSpecialTokens bool, trunc *TruncationParams, padding *PaddingParams) (*Encoding, error) {
+	tEncoding, tPairEncoding := encoding, pairEncoding
 
 	// 1. Truncate if needed
-	if t.trunc == nil {
-		tEncoding, tPairEncoding = encoding, pairEncoding
-	} else {
-		trunc := t.trunc
+	if trunc != nil {
 		var nAddedTokens int = 0 // number of AddedToken
-		if t.postProcessor != nil {
-			processor := t.postProcessor
+		if c.postProcessor != nil {
+			processor := c.postProcessor
 			nAddedTokens = processor.AddedTokens(pairEncoding != nil)
 		}
 
+		params := *trunc
 		if addSpecialTokens && nAddedTokens > 0 {
-			maxLength := trunc.MaxLength - nAddedTokens
-			params := &TruncationParams{
-				MaxLength: maxLength,
-				Strategy:  trunc.Strategy,
-				Stride:    trunc.Stride,
-			}
-			tEncoding, tPairEncoding = TruncateEncodings(encoding, pairEncoding, params)
-		} else {
-			tEncoding, tPairEncoding = TruncateEncodings(encoding, pairEncoding, trunc)
+			params.MaxLength -= nAddedTokens
+		}
+
+		encodings := []*Encoding{encoding}
+		if pairEncoding != nil {
+			encodings = append(encodings, pairEncoding)
+		}
+		if err := TruncateMultiEncodings(encodings, &params); err != nil {
+			return nil, err
 		}
 	}
 
 	// 2. Post-process
 	var finalEncoding *Encoding
-	if t.postProcessor != nil {
-		processor := t.postProcessor
+	if c.postProcessor != nil {
+		processor := c.postProcessor
 		finalEncoding = processor.Process(tEncoding, tPairEncoding, addSpecialTokens)
 	} else {
 		finalEncoding = DefaultProcess(tEncoding, tPairEncoding, addSpecialTokens)
 	}
 
 	// 3. Pad if needed
-	if t.padding == nil {
-		return finalEncoding
+	if padding == nil {
+		return finalEncoding, nil
 	}
 
 	var padEncodings []Encoding
 	encodings := []Encoding{*finalEncoding}
-	padEncodings = PadEncodings(encodings, *t.padding)
+	padEncodings = PadEncodings(encodings, *padding)
 	if len(padEncodings) == 1 {
-		return &padEncodings[0]
+		return &padEncodings[0], nil
+	} else {
+		return padEncodings[0].Merge(padEncodings[1:], true), nil
+	}
+}
+
+// postProcessMulti does post-processing logic for more than two sequences.
+func (c *tokenizerConfig) postProcessMulti(encodings []*Encoding, addSpecialTokens bool, trunc *TruncationParams, padding *PaddingParams) (*Encoding, error) {
+	processor, ok := c.postProcessor.(MultiPostProcessor)
+	if c.postProcessor != nil && !ok {
+		return nil, fmt.Errorf("Post-processor %T does not support %d sequences", c.postProcessor, len(encodings))
+	}
+
+	// 1. Truncate if needed
+	if trunc != nil {
+		params := *trunc
+		if addSpecialTokens && processor != nil {
+			params.MaxLength -= processor.AddedTokensMulti(len(encodings))
+		}
+		if err := TruncateMultiEncodings(encodings, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Post-process
+	var finalEncoding *Encoding
+	if processor != nil {
+		var err error
+		finalEncoding, err = processor.ProcessMulti(encodings, addSpecialTokens)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		return padEncodings[0].Merge(padEncodings[1:], true)
+		finalEncoding = DefaultProcessMulti(encodings)
 	}
+
+	// 3. Pad if needed
+	if padding != nil {
+		finalEncoding = &PadEncodings([]Encoding{*finalEncoding}, *padding)[0]
+	}
+
+	return finalEncoding, nil
 }
 
 // EncodeBatch encodes all sentences in concurrency
 func (t *Tokenizer) EncodeBatch(inputs []EncodeInput, addSpecialTokens bool) (retVal []Encoding, err error) {
+	encodings, err := t.EncodeBatchContext(context.Background(), inputs, WithBatchAddSpecialTokens(addSpecialTokens))
+	if err != nil {
+		if batchErr, ok := err.(*BatchError); ok {
+			return nil, batchErr.First()
+		}
+		return nil, err
+	}
+
+	return encodings, nil
+}
+
+// BatchOptions holds the options of a batch call.
+type BatchOptions struct {
+	// Maximum number of inputs processed at the same time.
+	// Zero or negative value means `runtime.GOMAXPROCS(0)`.
+	Concurrency int
+	// Whether to add special tokens
+	AddSpecialTokens bool
+	// Options applied to every input. They take precedence over `AddSpecialTokens`.
+	EncodeOptions []EncodeOption
+}
+
+type BatchOption func(o *BatchOptions)
+
+// WithConcurrency sets the maximum number of inputs processed at the same time.
+func WithConcurrency(n int) BatchOption {
+	return func(o *BatchOptions) {
+		o.Concurrency = n
+	}
+}
+
+// WithBatchAddSpecialTokens specifies whether to add special tokens.
+func WithBatchAddSpecialTokens(v bool) BatchOption {
+	return func(o *BatchOptions) {
+		o.AddSpecialTokens = v
+	}
+}
+
+// WithEncodeOptions sets the options used to encode every input of the batch.
+func WithEncodeOptions(opts ...EncodeOption) BatchOption {
+	return func(o *BatchOptions) {
+		o.EncodeOptions = append(o.EncodeOptions, opts...)
+	}
+}
+
+func DefaultBatchOptions() *BatchOptions {
+	return &BatchOptions{
+		Concurrency:      runtime.GOMAXPROCS(0),
+		AddSpecialTokens: false,
+	}
+}
+
+func newBatchOptions(opts ...BatchOption) *BatchOptions {
+	o := DefaultBatchOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	return o
+}
+
+// BatchError reports the inputs of a batch call that failed.
+type BatchError struct {
+	// Errors has the same length as the batch input. Errors[i] is nil
+	// if input i has been processed successfully.
+	Errors []error
+}
+
+// Error implements error interface.
+func (e *BatchError) Error() string {
+	var (
+		n     int
+		first int = -1
+	)
+	for i, err := range e.Errors {
+		if err != nil {
+			n++
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return fmt.Sprintf("0 of %d batch inputs failed", len(e.Errors))
+	}
+
+	return fmt.Sprintf("%d of %d batch inputs failed (input %d: %v)", n, len(e.Errors), first, e.Errors[first])
+}
+
+// Unwrap returns all the non-nil per-input errors.
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// First returns the error of the first failed input.
+func (e *BatchError) First() error {
+	for _, err := range e.Errors {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runBatch calls fn for every index in [0, n) using at most `concurrency` goroutines.
+// Indices not yet started when ctx is done get ctx.Err() as their error.
+// It returns a *BatchError if any index failed.
+func runBatch(ctx context.Context, n, concurrency int, fn func(i int) error) error {
 	var (
-		encodings []Encoding = make([]Encoding, len(inputs))
-		eg        errgroup.Group
-		mu        = &sync.Mutex{}
+		eg   errgroup.Group
+		errs = make([]error, n)
 	)
 
-	// Encoding concurrently
-	for i := range inputs {
+	eg.SetLimit(concurrency)
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
 		eg.Go(func() error {
-			e, err := t.Encode(inputs[i], addSpecialTokens)
-			if err != nil {
-				return err
+			err := ctx.Err()
+			if err == nil {
+				err = fn(i)
 			}
-			mu.Lock()
-			encodings[i] = *e
-			mu.Unlock()
+			errs[i] = err
 			return nil
 		})
 	}
+	eg.Wait()
 
-	if err := eg.Wait(); err != nil {
-		return nil, err
+	for _, err := range errs {
+		if err != nil {
+			return &BatchError{errs}
+		}
+	}
+
+	return nil
+}
+
+// EncodeBatchContext encodes all inputs with a bounded number of goroutines.
+//
+// Encoding stops picking up new inputs as soon as ctx is done. Inputs that
+// failed (or were not processed because of cancellation) are left empty in the
+// returned slice and reported in a *BatchError, so the caller can still use
+// all successful encodings. Padding, if any, is applied to the successful
+// encodings only.
+func (t *Tokenizer) EncodeBatchContext(ctx context.Context, inputs []EncodeInput, opts ...BatchOption) ([]Encoding, error) {
+	c := t.load()
+	o := newBatchOptions(opts...)
+	eo := c.newEncodeOptions(append([]EncodeOption{WithAddSpecialTokens(o.AddSpecialTokens)}, o.EncodeOptions...)...)
+	encodings := make([]Encoding, len(inputs))
+
+	err := runBatch(ctx, len(inputs), o.Concurrency, func(i int) error {
+		e, err := c.encode(inputs[i], eo)
+		if err != nil {
+			return err
+		}
+		encodings[i] = *e
+		return nil
+	})
+
+	var idx []int
+	for i := range encodings {
+		if err == nil || err.(*BatchError).Errors[i] == nil {
+			idx = append(idx, i)
+		}
 	}
 
 	// Do padding if included
-	if t.padding != nil {
-		encodings = PadEncodings(encodings, *t.padding)
+	if eo.Padding != nil {
+		var ok []Encoding
+		for _, i := range idx {
+			ok = append(ok, encodings[i])
+		}
+		for n, e := range PadEncodings(ok, *eo.Padding) {
+			encodings[idx[n]] = e
+		}
 	}
 
-	return encodings, nil
+	for _, i := range idx {
+		eo.strip(&encodings[i])
+	}
+
+	return encodings, err
 }
 
-// DecodeBatch decodes all sentences in concurrency
-func (t *Tokenizer) DecodeBatch(sentences [][]int, skipSpecialTokens bool) []string {
-	var decodings []string
-	var wg sync.WaitGroup
+// EncodeBatchOverflowing encodes all inputs like `EncodeBatchContext` and returns
+// every window of the truncated inputs as its own row: each encoding followed by
+// its `Overflowing` encodings. `overflowToSample[i]` is the index of the input
+// row `i` comes from.
+//
+// This is the usual setup for extractive question answering or long document
+// classification: encode (question, context) pairs with the `OnlySecond`
+// strategy and a stride, so that only the context is split into overlapping
+// windows. `SequenceRanges` of each row tell which tokens belong to the
+// question (sequence 0) and to the context (sequence 1).
+//
+// Rows of inputs that failed are left out; their errors are reported in the
+// returned `*BatchError` as for `EncodeBatchContext`.
+func (t *Tokenizer) EncodeBatchOverflowing(ctx context.Context, inputs []EncodeInput, opts ...BatchOption) (rows []Encoding, overflowToSample []int, err error) {
+	encodings, err := t.EncodeBatchContext(ctx, inputs, opts...)
+
+	var ok []Encoding
+	var idx []int
+	for i := range encodings {
+		if err == nil || err.(*BatchError).Errors[i] == nil {
+			ok = append(ok, encodings[i])
+			idx = append(idx, i)
+		}
+	}
+
+	rows, samples := FlattenOverflowing(ok)
+	overflowToSample = make([]int, len(samples))
+	for i, n := range samples {
+		overflowToSample[i] = idx[n]
+	}
 
-	wg.Add(len(sentences))
+	return rows, overflowToSample, err
+}
 
-	// Decoding concurrently
-	for i := 0; i < len(sentences); i++ {
-		go func(i int) {
-			defer wg.Done()
+// FlattenOverflowing returns each encoding followed by its `Overflowing`
+// encodings as separate rows, with their `Overflowing` field emptied.
+// `overflowToSample[i]` is the index of the encoding row `i` comes from.
+func FlattenOverflowing(encodings []Encoding) (rows []Encoding, overflowToSample []int) {
+	for i, e := range encodings {
+		overflowing := e.Overflowing
+		e.Overflowing = []Encoding{}
+		rows = append(rows, e)
+		overflowToSample = append(overflowToSample, i)
+
+		for _, o := range overflowing {
+			o.Overflowing = []Encoding{}
+			rows = append(rows, o)
+			overflowToSample = append(overflowToSample, i)
+		}
+	}
+
+	return rows, overflowToSample
+}
 
-			s := t.Decode(sentences[i], skipSpecialTokens)
-			decodings = append(decodings, s)
-		}(i)
+// EncodeBatchWithOptions encodes all inputs in concurrency using the tokenizer
+// configuration overridden by the given options.
+func (t *Tokenizer) EncodeBatchWithOptions(inputs []EncodeInput, opts ...EncodeOption) ([]Encoding, error) {
+	encodings, err := t.EncodeBatchContext(context.Background(), inputs, WithEncodeOptions(opts...))
+	if err != nil {
+		if batchErr, ok := err.(*BatchError); ok {
+			return nil, batchErr.First()
+		}
+		return nil, err
 	}
 
-	wg.Wait()
+	return encodings, nil
+}
 
+// DecodeBatch decodes all sentences in concurrency. The i-th output is
+// the decoding of the i-th input.
+//
+// Like `Decode`, it drops ids unknown to the vocabulary (`SkipUnknownIds`
+// policy). Use `DecodeBatchContext` to report or replace them instead.
+func (t *Tokenizer) DecodeBatch(sentences [][]int, skipSpecialTokens bool) []string {
+	decodings, _ := t.DecodeBatchContext(context.Background(), sentences, WithSkipSpecialTokens(skipSpecialTokens))
 	return decodings
 }
 
+// DecodeBatchContext decodes all sentences with a bounded number of goroutines.
+// The i-th output is the decoding of the i-th input.
+//
+// Sentences that failed (or were not processed because of cancellation) are
+// left empty and reported in a *BatchError.
+func (t *Tokenizer) DecodeBatchContext(ctx context.Context, sentences [][]int, opts ...DecodeOption) ([]string, error) {
+	c := t.load()
+	o := newDecodeOptions(opts...)
+	decodings := make([]string, len(sentences))
+
+	err := runBatch(ctx, len(sentences), o.Concurrency, func(i int) error {
+		tokens, err := c.idsToTokens(sentences[i], o)
+		if err != nil {
+			return err
+		}
+		decodings[i] = c.decodeTokens(tokens)
+		return nil
+	})
+
+	return decodings, err
+}
+
 // wordCount returns a map of word and its count
 func (t *Tokenizer) wordCount(trainer Model, files []string) (retVal map[string]int) {
 	// TODO: implement
@@ -721,12 +1443,16 @@ func NewTokenizerFromFile(file string) (retVal *Tokenizer) {
 }
 
 // Serialize serializes current Tokenizer to string
+//
+// NOTE. Not implemented; `pretrained.ToWriter` writes the `tokenizer.json` data.
 func (t *Tokenizer) Serialize(pretty bool) (retVal string) {
 	// TODO: implement
 	return
 }
 
 // Save saves the current tokenizer at the given path
+//
+// NOTE. Not implemented; `pretrained.ToFile` writes a `tokenizer.json` file.
 func (t *Tokenizer) Save(path string, pretty bool) (err error) {
 	// TODO: implement
 	return
@@ -846,8 +1572,10 @@ func (t *Tokenizer) Train(trainer Trainer, files []string) error {
 	model, specialTokens := trainer.Train(dict)
 
 	// Replace with trained model
-	t.model = model
-	t.AddSpecialTokens(specialTokens)
+	t.update(func(c *tokenizerConfig) {
+		c.model = model
+		c.addedVocabulary.AddSpecialTokens(specialTokens, c.model, c.normalizer)
+	})
 
 	return nil
 }
@@ -866,6 +1594,8 @@ func (t *Tokenizer) processChunk(offset int64, limit int64, filename string, cha
 	}
 	defer file.Close()
 
+	c := t.load()
+
 	// move the pointer of the file to the start of designated chunk
 	file.Seek(offset, 0) // 0 means relative to the origin of file
 
@@ -895,13 +1625,13 @@ func (t *Tokenizer) processChunk(offset int64, limit int64, filename string, cha
 		 *   log.Fatalf("call 'Encode' method error: %v\n", err)
 		 * } */
 
-		normalized, err := t.doNormalize(line)
+		normalized, err := c.doNormalize(line)
 		if err != nil {
 			log.Fatalf("call 'doNormalize' method error: %v\n", err)
 		}
 
 		pretok := NewPreTokenizedStringFromNS(normalized)
-		pretokenized, err := t.doPreTokenize(pretok)
+		pretokenized, err := c.doPreTokenize(pretok)
 		if err != nil {
 			log.Fatalf("call 'doPreTokenize' method error: %v\n", err)
 		}
@@ -1054,8 +1784,10 @@ func (t *Tokenizer) CTrain(trainer Trainer, files []string) error {
 	model, specialTokens := trainer.Train(words)
 
 	// Replace with trained model
-	t.model = model
-	t.AddSpecialTokens(specialTokens)
+	t.update(func(c *tokenizerConfig) {
+		c.model = model
+		c.addedVocabulary.AddSpecialTokens(specialTokens, c.model, c.normalizer)
+	})
 
 	return nil
 }
@@ -1077,9 +1809,15 @@ func (t *Tokenizer) EncodeSingle(input string, addSpecialTokensOpt ...bool) (*En
 		addSpecialTokens = addSpecialTokensOpt[0]
 	}
 
+	return t.EncodeSingleWithOptions(input, WithAddSpecialTokens(addSpecialTokens))
+}
+
+// EncodeSingleWithOptions encodes a single input string using the tokenizer
+// configuration overridden by the given options.
+func (t *Tokenizer) EncodeSingleWithOptions(input string, opts ...EncodeOption) (*Encoding, error) {
 	encodeInput := NewSingleEncodeInput(NewInputSequence(input))
 
-	return t.Encode(encodeInput, addSpecialTokens)
+	return t.EncodeWithOptions(encodeInput, opts...)
 }
 
 // EncodePair encodes a pair of string sequences.
@@ -1095,11 +1833,17 @@ func (t *Tokenizer) EncodePair(input, pair string, addSpecialTokensOpt ...bool)
 		addSpecialTokens = addSpecialTokensOpt[0]
 	}
 
+	return t.EncodePairWithOptions(input, pair, WithAddSpecialTokens(addSpecialTokens))
+}
+
+// EncodePairWithOptions encodes a pair of string sequences using the tokenizer
+// configuration overridden by the given options.
+func (t *Tokenizer) EncodePairWithOptions(input, pair string, opts ...EncodeOption) (*Encoding, error) {
 	seq := NewInputSequence(input)
 	pseq := NewInputSequence(pair)
 	encodeInput := NewDualEncodeInput(seq, pseq)
 
-	return t.Encode(encodeInput, addSpecialTokens)
+	return t.EncodeWithOptions(encodeInput, opts...)
 }
 
 // Tokenize slices input string into tokens.
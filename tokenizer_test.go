@@ -0,0 +1,331 @@
+package tokenizer_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/normalizer"
+	"github.com/sugarme/tokenizer/pretrained"
+	"github.com/sugarme/tokenizer/processor"
+)
+
+// failingPreTokenizer wraps a PreTokenizer and fails on inputs containing "fail".
+type failingPreTokenizer struct {
+	tokenizer.PreTokenizer
+}
+
+func (p failingPreTokenizer) PreTokenize(pretok *tokenizer.PreTokenizedString) (*tokenizer.PreTokenizedString, error) {
+	for _, split := range pretok.GetSplits(normalizer.OriginalTarget, tokenizer.Byte) {
+		if strings.Contains(split.Value, "fail") {
+			return nil, errors.New("failing input")
+		}
+	}
+	return p.PreTokenizer.PreTokenize(pretok)
+}
+
+func batchInputs(sentences ...string) []tokenizer.EncodeInput {
+	var inputs []tokenizer.EncodeInput
+	for _, s := range sentences {
+		inputs = append(inputs, tokenizer.NewSingleEncodeInput(tokenizer.NewInputSequence(s)))
+	}
+	return inputs
+}
+
+func TestEncodeBatchContext(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	tk.WithPreTokenizer(failingPreTokenizer{tk.GetPreTokenizer()})
+
+	inputs := batchInputs("hello world", "this will fail", "a b c", "far away")
+	encodings, err := tk.EncodeBatchContext(context.Background(), inputs, tokenizer.WithConcurrency(2))
+
+	var batchErr *tokenizer.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("want *BatchError, got %v", err)
+	}
+	for i, e := range batchErr.Errors {
+		if (e != nil) != (i == 1) {
+			t.Errorf("input %d: unexpected error %v", i, e)
+		}
+	}
+
+	want := [][]string{{"hello", "world"}, nil, {"a", "b", "c"}, {"far", "away"}}
+	for i, w := range want {
+		if i == 1 {
+			continue
+		}
+		if got := encodings[i].Tokens; strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("input %d: want %v, got %v", i, w, got)
+		}
+	}
+}
+
+func TestBatchError_NoErrors(t *testing.T) {
+	err := &tokenizer.BatchError{Errors: make([]error, 2)}
+	if got, want := err.Error(), "0 of 2 batch inputs failed"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestEncodeBatchContext_Canceled(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := tk.EncodeBatchContext(ctx, batchInputs("hello", "world"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestEncodeBatch(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	encodings, err := tk.EncodeBatch(batchInputs("hello", "far away"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := encodings[1].Tokens; strings.Join(got, " ") != "[CLS] far away [SEP]" {
+		t.Errorf("unexpected tokens %v", got)
+	}
+
+	tk.WithPreTokenizer(failingPreTokenizer{tk.GetPreTokenizer()})
+	if _, err := tk.EncodeBatch(batchInputs("hello", "fail"), true); err == nil {
+		t.Errorf("want error, got nil")
+	}
+}
+
+func TestEncodeBatch_PadToMultipleOf(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	tk.WithPadding(&tokenizer.PaddingParams{
+		Strategy:        *tokenizer.NewPaddingStrategy(tokenizer.WithBatchLongest()),
+		Direction:       tokenizer.Right,
+		PadToken:        "[PAD]",
+		PadToMultipleOf: 8,
+	})
+
+	encodings, err := tk.EncodeBatch(batchInputs("hello", "yesterday i saw a cat far away from home"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, en := range encodings {
+		if en.Len() != 16 {
+			t.Errorf("encoding %d: want 16 tokens, got %d", i, en.Len())
+		}
+	}
+}
+
+func TestEncodeBatchOverflowing(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 12, Strategy: tokenizer.OnlySecond, Stride: 2})
+
+	inputs := []tokenizer.EncodeInput{
+		tokenizer.NewDualEncodeInput(tokenizer.NewInputSequence("who saw a cat"), tokenizer.NewInputSequence("yesterday i saw a cat far away from home and it ran away quickly")),
+		tokenizer.NewDualEncodeInput(tokenizer.NewInputSequence("where"), tokenizer.NewInputSequence("at home")),
+	}
+
+	rows, overflowToSample, err := tk.EncodeBatchOverflowing(context.Background(), inputs, tokenizer.WithBatchAddSpecialTokens(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{0, 0, 0, 0, 1}; !reflect.DeepEqual(want, overflowToSample) {
+		t.Fatalf("overflow to sample: want %v, got %v", want, overflowToSample)
+	}
+
+	wantContexts := [][]string{
+		{"yesterday", "i", "saw", "a", "cat"},
+		{"a", "cat", "far", "away", "from"},
+		{"away", "from", "home", "and", "it"},
+		{"and", "it", "ran", "away", "quickly"},
+		{"at", "home"},
+	}
+	for i, row := range rows {
+		if len(row.Overflowing) != 0 {
+			t.Errorf("row %d: unexpected overflowing", i)
+		}
+
+		question, err := row.SequenceRange(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if question[0] != 1 {
+			t.Errorf("row %d: question should start after [CLS], got %v", i, question)
+		}
+
+		contextRange, err := row.SequenceRange(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, idx := range contextRange {
+			got = append(got, row.Tokens[idx])
+		}
+		if !reflect.DeepEqual(wantContexts[i], got) {
+			t.Errorf("row %d: want context %v, got %v", i, wantContexts[i], got)
+		}
+	}
+}
+
+func TestDecodeBatch(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	var (
+		sentences [][]int
+		want      []string
+	)
+	for i := 0; i < 50; i++ {
+		vocab := []string{"hello", "far", "away", "world", "yesterday"}
+		var words []string
+		for j := 0; j <= i%4; j++ {
+			words = append(words, vocab[(i+j)%len(vocab)])
+		}
+		en, err := tk.EncodeSingle(strings.Join(words, " "))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sentences = append(sentences, en.Ids)
+		want = append(want, strings.Join(words, " "))
+	}
+
+	got := tk.DecodeBatch(sentences, false)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sentence %d: want %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestDecodeWithOptions_UnknownIds(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	helloId, _ := tk.TokenToId("hello")
+	unknownId := tk.GetVocabSize(true) + 10
+	ids := []int{helloId, unknownId, helloId}
+
+	if got := tk.Decode(ids, false); got != "hello hello" {
+		t.Errorf("want %q, got %q", "hello hello", got)
+	}
+
+	_, err := tk.DecodeWithOptions(ids, tokenizer.WithUnknownIdPolicy(tokenizer.ErrorOnUnknownIds))
+	if !errors.Is(err, tokenizer.ErrUnknownId) {
+		t.Errorf("want ErrUnknownId, got %v", err)
+	}
+
+	got, err := tk.DecodeWithOptions(ids, tokenizer.WithReplacement("<?>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "hello <?> hello" {
+		t.Errorf("want %q, got %q", "hello <?> hello", got)
+	}
+
+	decodings, err := tk.DecodeBatchContext(context.Background(), [][]int{{helloId}, ids}, tokenizer.WithUnknownIdPolicy(tokenizer.ErrorOnUnknownIds))
+	var batchErr *tokenizer.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Errors[0] != nil || batchErr.Errors[1] == nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decodings[0] != "hello" {
+		t.Errorf("want %q, got %q", "hello", decodings[0])
+	}
+}
+
+func TestTruncateMultiEncodings(t *testing.T) {
+	tests := []struct {
+		lengths   []int
+		maxLength int
+		want      []int
+	}{
+		{[]int{2, 10, 6}, 12, []int{2, 5, 5}},
+		{[]int{3, 10}, 8, []int{3, 5}},
+		{[]int{10, 10}, 9, []int{4, 5}},
+		{[]int{3, 4}, 10, []int{3, 4}},
+	}
+
+	for _, tt := range tests {
+		var encodings []*tokenizer.Encoding
+		for _, n := range tt.lengths {
+			tokens := make([]tokenizer.Token, n)
+			for i := range tokens {
+				tokens[i] = tokenizer.Token{Id: i, Value: "a", Offsets: []int{i, i + 1}}
+			}
+			encodings = append(encodings, tokenizer.NewEncodingFromTokens(tokens, 0))
+		}
+
+		params := &tokenizer.TruncationParams{MaxLength: tt.maxLength, Strategy: tokenizer.LongestFirst}
+		if err := tokenizer.TruncateMultiEncodings(encodings, params); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []int
+		for _, e := range encodings {
+			got = append(got, e.Len())
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("lengths %v, max %d: want %v, got %v", tt.lengths, tt.maxLength, tt.want, got)
+		}
+	}
+}
+
+func TestEncodePair_SmallTruncationBudget(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+
+	// 1 token left for both sequences once [CLS] and [SEP]s are added
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 4, Strategy: tokenizer.LongestFirst})
+	en, err := tk.EncodePair("hello world", "far away", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"[CLS]", "[SEP]", "far", "[SEP]"}; !reflect.DeepEqual(en.Tokens, want) {
+		t.Errorf("want %q, got %q", want, en.Tokens)
+	}
+
+	// Stride larger than the budget of the sequences
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 6, Strategy: tokenizer.LongestFirst, Stride: 3})
+	if en, err = tk.EncodePair("hello world", "far away", true); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"[CLS]", "hello", "[SEP]", "far", "away", "[SEP]"}; !reflect.DeepEqual(en.Tokens, want) {
+		t.Errorf("want %q, got %q", want, en.Tokens)
+	}
+
+	// Errors are returned instead of exiting
+	tk.WithTruncation(&tokenizer.TruncationParams{MaxLength: 4, Strategy: tokenizer.OnlySecond})
+	if _, err := tk.EncodePair("hello world", "far away", true); err == nil {
+		t.Errorf("want error truncating a too short second sequence")
+	}
+}
+
+func TestEncode_MultiSequences(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	builder := processor.DefaultTemplateProcessing().Builder()
+	builder.NewSingle("[CLS] $A [SEP]")
+	builder.NewPair("[CLS] $A [SEP] $B:1 [SEP]:1")
+	builder.NewMulti(3, "[CLS] $A [SEP] $B:1 [SEP]:1 $C:2 [SEP]:2")
+	builder.NewSpecialTokens([]tokenizer.Token{{Id: 101, Value: "[CLS]"}, {Id: 102, Value: "[SEP]"}})
+	tk.WithPostProcessor(builder.Build())
+
+	input := tokenizer.NewMultiEncodeInput(
+		tokenizer.NewInputSequence("hello"),
+		tokenizer.NewInputSequence("world"),
+		tokenizer.NewInputSequence("hello world"),
+	)
+	en, err := tk.Encode(input, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{101, 7592, 102, 2088, 102, 7592, 2088, 102}; !reflect.DeepEqual(want, en.Ids) {
+		t.Errorf("want ids %v, got %v", want, en.Ids)
+	}
+	if want := []int{0, 0, 0, 1, 1, 2, 2, 2}; !reflect.DeepEqual(want, en.TypeIds) {
+		t.Errorf("want type ids %v, got %v", want, en.TypeIds)
+	}
+	if r, ok := en.SequenceRanges[2]; !ok || !reflect.DeepEqual(tokenizer.NewRange(5, 7), r) {
+		t.Errorf("want range [5, 7) for sequence 2, got %v", r)
+	}
+}
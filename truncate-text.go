@@ -0,0 +1,83 @@
+package tokenizer
+
+import (
+	"errors"
+	"sort"
+)
+
+// TruncateText returns the longest prefix (`TruncateRight`) or suffix
+// (`TruncateLeft`) of the text that fits in `maxTokens` tokens, as a substring
+// of the original text.
+//
+// The text is encoded once and cut between tokens, using their offsets, so
+// added tokens are never split, and neither are characters split into several
+// tokens (e.g. byte-level BPE). As tokens may change when the text is cut, the
+// cut text is counted again; if it doesn't fit, the cut is binary searched
+// among the earlier token boundaries. Special tokens added by the
+// post-processor are not counted: subtract `PostProcessor.AddedTokens` from
+// the budget if needed.
+func (t *Tokenizer) TruncateText(text string, maxTokens int, direction TruncationDirection) (string, error) {
+	if maxTokens < 0 {
+		return "", errors.New("Tokenizer.TruncateText() failed: max tokens must not be negative")
+	}
+
+	en, err := t.EncodeSingleWithOptions(text,
+		WithAddSpecialTokens(false),
+		WithTruncationParams(nil),
+		WithPaddingParams(nil),
+		WithOffsetType(Byte),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	c := chunker{text: text, offsets: en.Offsets}
+	n := len(c.offsets)
+	if n <= maxTokens {
+		return text, nil
+	}
+
+	// Candidate cuts, from the one keeping the most tokens
+	var cuts []string
+	switch direction {
+	case TruncateLeft:
+		// Keep tokens [start, n)
+		for start := n - maxTokens; start < n; start++ {
+			if c.canCut(start) {
+				cuts = append(cuts, text[c.runeStart(c.offsets[start][0]):])
+			}
+		}
+
+	default:
+		// Keep tokens [0, stop)
+		for stop := maxTokens; stop > 0; stop-- {
+			if c.canCut(stop) {
+				cuts = append(cuts, text[:c.runeEnd(c.offsets[stop-1][1])])
+			}
+		}
+	}
+
+	// First cut that fits, assuming that shorter texts have fewer tokens. The
+	// first one usually does.
+	var countErr error
+	fits := func(i int) bool {
+		if countErr != nil {
+			return true
+		}
+		count, err := t.CountTokens(cuts[i], false)
+		countErr = err
+		return count <= maxTokens
+	}
+	i := 0
+	if len(cuts) > 0 && !fits(0) {
+		i = 1 + sort.Search(len(cuts)-1, func(i int) bool { return fits(i + 1) })
+	}
+	if countErr != nil {
+		return "", countErr
+	}
+	if i == len(cuts) {
+		return "", nil
+	}
+
+	return cuts[i], nil
+}
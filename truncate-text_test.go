@@ -0,0 +1,61 @@
+package tokenizer_test
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sugarme/tokenizer"
+	"github.com/sugarme/tokenizer/pretrained"
+)
+
+func TestTruncateText(t *testing.T) {
+	tk := pretrained.BertBaseUncased()
+	text := "Yesterday I saw a [MASK] far away from home."
+
+	tests := []struct {
+		maxTokens int
+		direction tokenizer.TruncationDirection
+		want      string
+	}{
+		{100, tokenizer.TruncateRight, text},
+		{4, tokenizer.TruncateRight, "Yesterday I saw a"},
+		{5, tokenizer.TruncateRight, "Yesterday I saw a [MASK]"},
+		{3, tokenizer.TruncateLeft, "from home."},
+		{6, tokenizer.TruncateLeft, "[MASK] far away from home."},
+		{0, tokenizer.TruncateLeft, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := tk.TruncateText(text, tt.maxTokens, tt.direction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("max %d, direction %v: want %q, got %q", tt.maxTokens, tt.direction, tt.want, got)
+		}
+	}
+}
+
+func TestTruncateText_MultiByte(t *testing.T) {
+	tk := tokenizer.NewTokenizer(byteModel{})
+	text := "a日本語b"
+
+	for maxTokens := 0; maxTokens <= len(text); maxTokens++ {
+		for _, direction := range []tokenizer.TruncationDirection{tokenizer.TruncateRight, tokenizer.TruncateLeft} {
+			got, err := tk.TruncateText(text, maxTokens, direction)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("max %d, direction %v: %q splits a character", maxTokens, direction, got)
+			}
+			if n, _ := tk.CountTokens(got, false); n > maxTokens {
+				t.Errorf("max %d, direction %v: %q has %d tokens", maxTokens, direction, got, n)
+			}
+		}
+	}
+
+	if got, _ := tk.TruncateText(text, 5, tokenizer.TruncateRight); got != "a日" {
+		t.Errorf("want %q, got %q", "a日", got)
+	}
+}
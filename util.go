@@ -1,14 +1,17 @@
 package tokenizer
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
+	"sort"
 )
 
 type TruncationParams struct {
 	MaxLength int
 	Strategy  TruncationStrategy
 	Stride    int
+	Direction TruncationDirection
 }
 
 type PaddingParams struct {
@@ -17,6 +20,46 @@ type PaddingParams struct {
 	PadId     int
 	PadTypeId int
 	PadToken  string
+	// PadToMultipleOf rounds the padding length up to a multiple of it. Zero means no rounding.
+	PadToMultipleOf int
+}
+
+// paddingParamsJSON is the `tokenizer.json` representation of PaddingParams.
+type paddingParamsJSON struct {
+	Strategy        interface{} `json:"strategy"`
+	Direction       string      `json:"direction"`
+	PadToMultipleOf *int        `json:"pad_to_multiple_of"`
+	PadId           int         `json:"pad_id"`
+	PadTypeId       int         `json:"pad_type_id"`
+	PadToken        string      `json:"pad_token"`
+}
+
+// MarshalJSON implements json.Marshaler using the `tokenizer.json` format.
+func (p PaddingParams) MarshalJSON() ([]byte, error) {
+	var strategy interface{} = "BatchLongest"
+	if p.Strategy.Name == "Fixed" {
+		strategy = map[string]interface{}{"Fixed": p.Strategy.Value}
+	}
+
+	direction := "Right"
+	if p.Direction == Left {
+		direction = "Left"
+	}
+
+	var multiple *int
+	if p.PadToMultipleOf > 0 {
+		v := p.PadToMultipleOf
+		multiple = &v
+	}
+
+	return json.Marshal(paddingParamsJSON{
+		Strategy:        strategy,
+		Direction:       direction,
+		PadToMultipleOf: multiple,
+		PadId:           p.PadId,
+		PadTypeId:       p.PadTypeId,
+		PadToken:        p.PadToken,
+	})
 }
 
 // PaddingStrategy is a enum of either
@@ -75,80 +118,163 @@ const (
 	OnlySecond
 )
 
+// TruncationDirection is enum of int type represents which side of a sequence
+// is removed when truncating.
+type TruncationDirection int
+
+const (
+	// TruncateRight removes tokens at the end of the sequence
+	TruncateRight TruncationDirection = iota
+	// TruncateLeft removes tokens at the beginning of the sequence
+	TruncateLeft
+)
+
 const (
 	SecondSequenceNotProvided = "Truncation error: Second sequence not provided"
 	SequenceTooShort          = "Truncation error: Sequence to truncate too short to respect the provided max_length"
 )
 
+// TruncateEncodings truncates a sequence and its (optional) pair so that their
+// total length fits in `params.MaxLength`.
+//
+// NOTE. It exits on truncation errors; `TruncateMultiEncodings` returns them.
 func TruncateEncodings(encoding, pairEncoding *Encoding, params *TruncationParams) (tEncoding, tPairEncoding *Encoding) {
-	var (
-		totalLength int
-		toRemove    int
-		err         error
-	)
+	encodings := []*Encoding{encoding}
+	if pairEncoding != nil {
+		encodings = append(encodings, pairEncoding)
+	}
+
+	if err := TruncateMultiEncodings(encodings, params); err != nil {
+		log.Fatal(err)
+	}
+
+	return encoding, pairEncoding
+}
 
+// TruncateMultiEncodings truncates the given encodings, i.e. the sequences of
+// one input, so that their total length fits in `params.MaxLength`.
+//
+// With `LongestFirst`, the budget is spread across sequences: the longest
+// sequences are truncated first, so that short sequences are kept whole.
+// `OnlyFirst` and `OnlySecond` truncate only the first or second sequence.
+func TruncateMultiEncodings(encodings []*Encoding, params *TruncationParams) error {
 	if params.MaxLength == 0 {
-		return encoding, pairEncoding
+		return nil
 	}
 
-	totalLength = len(encoding.GetIds())
-	if pairEncoding != nil {
-		totalLength = len(encoding.GetIds()) + len(pairEncoding.GetIds())
+	lengths := make([]int, len(encodings))
+	totalLength := 0
+	for i, e := range encodings {
+		lengths[i] = e.Len()
+		totalLength += lengths[i]
 	}
 
-	if totalLength < params.MaxLength {
-		return encoding, pairEncoding
+	if totalLength <= params.MaxLength {
+		return nil
 	}
 
-	toRemove = totalLength - params.MaxLength
+	toRemove := totalLength - params.MaxLength
 
 	switch params.Strategy {
 	case LongestFirst:
-		nFirst := len(encoding.GetIds())
-		nSecond := 0
-		if pairEncoding != nil {
-			nSecond = len(pairEncoding.GetIds())
-		}
-
-		for i := 0; i < toRemove; i++ {
-			if nFirst > nSecond {
-				nFirst -= 1
+		for i, n := range truncationBudgets(lengths, params.MaxLength) {
+			if n < lengths[i] {
+				if err := truncateSequence(encodings[i], n, params); err != nil {
+					return err
+				}
 			}
-			nSecond -= 1
 		}
 
-		encoding.Truncate(nFirst, params.Stride)
-		if pairEncoding != nil {
-			pairEncoding.Truncate(nSecond, params.Stride)
+	case OnlyFirst, OnlySecond:
+		idx := 0
+		if params.Strategy == OnlySecond {
+			idx = 1
+		}
+		if idx >= len(encodings) {
+			return errors.New(SecondSequenceNotProvided)
 		}
 
-	case OnlyFirst, OnlySecond:
-		var truncateFunc = func(target *Encoding) (*Encoding, error) {
-			targetLength := len(target.GetIds())
-			if targetLength > toRemove {
-				target.Truncate(targetLength-toRemove, params.Stride)
-				return target, nil
-			} else {
-				err := errors.New(SequenceTooShort)
-				return nil, err
-			}
+		targetLength := lengths[idx]
+		if targetLength <= toRemove {
+			return errors.New(SequenceTooShort)
+		}
+		if err := truncateSequence(encodings[idx], targetLength-toRemove, params); err != nil {
+			return err
 		}
+	}
 
-		if params.Strategy == OnlyFirst {
-			encoding, err = truncateFunc(encoding)
-		} else if pairEncoding != nil {
-			pairEncoding, err = truncateFunc(pairEncoding)
-		} else {
-			err = errors.New(SecondSequenceNotProvided)
+	return nil
+}
+
+// truncateSequence truncates one of the sequences of an input to n tokens. The
+// budget of a sequence can be smaller than the stride, or even zero: the stride
+// is then clamped to the budget, and a zero budget leaves the sequence empty,
+// all its tokens overflowing.
+func truncateSequence(e *Encoding, n int, params *TruncationParams) error {
+	if n > 0 {
+		stride := params.Stride
+		if stride >= n {
+			stride = n - 1
 		}
+		_, err := e.TruncateWithDirection(n, stride, params.Direction)
+		return err
+	}
 
+	overflowing := e.slice(0, len(e.Ids))
+	overflowing.Overflowing = make([]Encoding, 0)
+
+	kept := e.slice(0, 0)
+	e.Ids = kept.Ids
+	e.TypeIds = kept.TypeIds
+	e.Tokens = kept.Tokens
+	e.Offsets = kept.Offsets
+	e.SpecialTokenMask = kept.SpecialTokenMask
+	e.AttentionMask = kept.AttentionMask
+	e.Words = kept.Words
+	e.Overflowing = []Encoding{overflowing}
+
+	return nil
+}
+
+// truncationBudgets returns the length of each sequence after truncating
+// `lengths` to a total of `maxLength`, removing tokens from the longest
+// sequences first. When sequences can't be truncated to exactly the same
+// length, the remaining tokens go to the longest ones, and the last ones on ties.
+func truncationBudgets(lengths []int, maxLength int) []int {
+	budgets := make([]int, len(lengths))
+	copy(budgets, lengths)
+
+	order := make([]int, len(lengths))
+	for i := range order {
+		order[i] = i
 	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lengths[order[a]] < lengths[order[b]]
+	})
+
+	remaining := maxLength
+	for k, idx := range order {
+		n := len(order) - k
+		if lengths[idx]*n <= remaining {
+			// This sequence and the shorter ones are kept whole.
+			remaining -= lengths[idx]
+			continue
+		}
 
-	if err != nil {
-		log.Fatal(err)
+		// All sequences from here get the same share, the extra tokens
+		// going to the longest ones.
+		share, extra := remaining/n, remaining%n
+		for j := len(order) - 1; j >= k; j-- {
+			budgets[order[j]] = share
+			if extra > 0 {
+				budgets[order[j]]++
+				extra--
+			}
+		}
+		break
 	}
 
-	return encoding, pairEncoding
+	return budgets
 }
 
 func PadEncodings(encodings []Encoding, params PaddingParams) []Encoding {
@@ -171,6 +297,10 @@ func PadEncodings(encodings []Encoding, params PaddingParams) []Encoding {
 		padLength = max
 	}
 
+	if params.PadToMultipleOf > 0 && padLength%params.PadToMultipleOf != 0 {
+		padLength += params.PadToMultipleOf - padLength%params.PadToMultipleOf
+	}
+
 	// TODO: implement concurrency with for loop
 	var newEncodings []Encoding
 	for _, e := range encodings {